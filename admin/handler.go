@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	db StatsReporter
+}
+
+type StatsReporter interface {
+	Stats() sql.DBStats
+}
+
+func New(db StatsReporter) *Handler {
+	return &Handler{db: db}
+}
+
+type DBStats struct {
+	MaxOpenConnections int    `json:"max_open_connections" example:"25"`
+	OpenConnections    int    `json:"open_connections" example:"4"`
+	InUse              int    `json:"in_use" example:"1"`
+	Idle               int    `json:"idle" example:"3"`
+	WaitCount          int64  `json:"wait_count" example:"0"`
+	WaitDuration       string `json:"wait_duration" example:"0s"`
+	WaitDurationMillis int64  `json:"wait_duration_ms" example:"0"`
+	MaxIdleClosed      int64  `json:"max_idle_closed" example:"0"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed" example:"0"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed" example:"0"`
+}
+
+// DBStatsHandler
+//
+//	@Summary		Get database pool statistics
+//	@Description	Get connection pool statistics used to size the pool under load
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	DBStats
+//	@Router			/api/v1/admin/db/stats [get]
+func (h *Handler) DBStatsHandler(c echo.Context) error {
+	s := h.db.Stats()
+	return c.JSON(http.StatusOK, DBStats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration.String(),
+		WaitDurationMillis: s.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	})
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StubStats struct {
+	stats sql.DBStats
+}
+
+func (s StubStats) Stats() sql.DBStats {
+	return s.stats
+}
+
+func TestDBStats(t *testing.T) {
+	t.Run("given pool statistics should return them as json", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/admin/db/stats")
+
+		h := New(StubStats{stats: sql.DBStats{
+			MaxOpenConnections: 25,
+			OpenConnections:    5,
+			InUse:              2,
+			Idle:               3,
+			WaitCount:          7,
+			WaitDuration:       1500 * time.Millisecond,
+		}})
+
+		h.DBStatsHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+		var got DBStats
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		want := DBStats{
+			MaxOpenConnections: 25,
+			OpenConnections:    5,
+			InUse:              2,
+			Idle:               3,
+			WaitCount:          7,
+			WaitDuration:       "1.5s",
+			WaitDurationMillis: 1500,
+		}
+		if got != want {
+			t.Errorf("expected %+v but got %+v", want, got)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// Command reconcile compares every wallet's cached balance with the sum of
+// its ledger entries once, records a report and prints it as JSON. It exits
+// with status 1 when discrepancies were found and left unfixed, or a fix
+// failed, so it can be run from cron or after a restore.
+//
+// Usage:
+//
+//	reconcile [-fix]
+//
+// With -fix, each discrepancy is closed by an adjustment entry; cached
+// balances are never changed. It reads the same configuration as the API.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/logging"
+	"github.com/openmymai/fun-exercise-api/postgres"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "record an adjustment entry for every discrepancy")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log))
+
+	p, err := postgres.New(cfg.Database)
+	if err != nil {
+		fatal("database connection failed", err)
+	}
+	defer p.Close()
+
+	ctx := context.Background()
+	if err := p.CheckSchemaVersion(ctx); err != nil {
+		fatal("database schema check failed", err)
+	}
+	report, err := p.Reconcile(ctx, *fix)
+	if err != nil {
+		fatal("reconciliation failed", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fatal("writing report failed", err)
+	}
+	if (report.DiscrepancyCount > 0 && !report.Fix) || report.FailedFixes() > 0 {
+		p.Close()
+		os.Exit(1)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
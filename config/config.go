@@ -0,0 +1,626 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the full set of settings the API needs to start.
+//
+// Values are resolved in the following order, later sources winning:
+// built-in defaults, the file named by CONFIG_FILE (config.yaml by
+// default; YAML, or TOML if the name ends in .toml), the .env file and
+// finally the process environment. CONFIG_FILE itself may be set in either
+// of the last two.
+type Config struct {
+	Server    Server          `yaml:"server"`
+	Database  Database        `yaml:"database"`
+	Log       Log             `yaml:"log"`
+	Auth      Auth            `yaml:"auth"`
+	Tracing   Tracing         `yaml:"tracing"`
+	RateLimit RateLimit       `yaml:"rate_limit"`
+	CORS      CORS            `yaml:"cors"`
+	Security  Security        `yaml:"security"`
+	Billing   Billing         `yaml:"billing"`
+	Interest  Interest        `yaml:"interest"`
+	Crypto    Crypto          `yaml:"crypto"`
+	Transfers Transfers       `yaml:"transfers"`
+	Reconcile Reconcile       `yaml:"reconcile"`
+	Features  map[string]bool `yaml:"features" env:"FEATURES"`
+}
+
+type Server struct {
+	Addr            string        `yaml:"addr" env:"LISTEN_ADDR"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// SwaggerEnabled serves the swagger UI under /swagger/*. Switch it off
+	// in production.
+	SwaggerEnabled bool `yaml:"swagger_enabled" env:"SWAGGER_ENABLED"`
+	TLS            TLS  `yaml:"tls"`
+}
+
+// TLS is enabled when both files are set. The files are checked for
+// changes every ReloadInterval so renewed certificates need no restart.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL"`
+}
+
+// Enabled reports whether the server should listen with TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+type Database struct {
+	URL             string        `yaml:"url" env:"DATABASE_URL"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	QueryTimeout    time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+	AutoMigrate     bool          `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Redact is one of none, pii (names) or all (names and amounts).
+	Redact string `yaml:"redact" env:"LOG_REDACT"`
+}
+
+type Tracing struct {
+	// Exporter is one of none, stdout or otlp.
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type RateLimit struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Backend is memory for a single instance or postgres to share limits
+	// between instances.
+	Backend string `yaml:"backend" env:"RATE_LIMIT_BACKEND"`
+	// Rates are tokens per second; bursts are bucket sizes.
+	DefaultRate   float64 `yaml:"default_rate" env:"RATE_LIMIT_DEFAULT_RATE"`
+	DefaultBurst  int     `yaml:"default_burst" env:"RATE_LIMIT_DEFAULT_BURST"`
+	MutatingRate  float64 `yaml:"mutating_rate" env:"RATE_LIMIT_MUTATING_RATE"`
+	MutatingBurst int     `yaml:"mutating_burst" env:"RATE_LIMIT_MUTATING_BURST"`
+	// Routes overrides the limit for a single endpoint, keyed by method and
+	// route pattern, e.g. "POST /api/v1/wallets".
+	Routes map[string]RateLimitRule `yaml:"routes"`
+}
+
+type RateLimitRule struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type CORS struct {
+	AllowOrigins     []string      `yaml:"allow_origins" env:"CORS_ALLOW_ORIGINS"`
+	AllowMethods     []string      `yaml:"allow_methods" env:"CORS_ALLOW_METHODS"`
+	AllowHeaders     []string      `yaml:"allow_headers" env:"CORS_ALLOW_HEADERS"`
+	ExposeHeaders    []string      `yaml:"expose_headers" env:"CORS_EXPOSE_HEADERS"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"`
+}
+
+type Security struct {
+	// HSTSMaxAge is in seconds; 0 disables the header.
+	HSTSMaxAge            int  `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	HSTSIncludeSubdomains bool `yaml:"hsts_include_subdomains" env:"HSTS_INCLUDE_SUBDOMAINS"`
+	// ContentSecurityPolicy applies to API responses; the swagger UI has
+	// its own policy.
+	ContentSecurityPolicy string `yaml:"content_security_policy" env:"CONTENT_SECURITY_POLICY"`
+	// TrustedProxies are the CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header names the client. Without any, the client is
+	// the connection's peer address and forwarding headers are ignored.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+// Billing sets the terms of credit card statements.
+type Billing struct {
+	// StatementDay is the day of the month statements close on, 1 to 28.
+	StatementDay   int `yaml:"statement_day" env:"BILLING_STATEMENT_DAY"`
+	PaymentDueDays int `yaml:"payment_due_days" env:"BILLING_PAYMENT_DUE_DAYS"`
+	// The minimum payment is MinimumPaymentRate of the amount owed, but at
+	// least MinimumPayment, and never more than the amount owed.
+	MinimumPaymentRate float64 `yaml:"minimum_payment_rate" env:"BILLING_MINIMUM_PAYMENT_RATE"`
+	MinimumPayment     float64 `yaml:"minimum_payment" env:"BILLING_MINIMUM_PAYMENT"`
+	LateFee            float64 `yaml:"late_fee" env:"BILLING_LATE_FEE"`
+	// Interval is how often the scheduler looks for statements to close
+	// and late fees to post.
+	Interval time.Duration `yaml:"interval" env:"BILLING_INTERVAL"`
+}
+
+// Interest sets how wallets whose type earns interest accrue it. Interest
+// accrues daily on the end-of-day balance and is posted monthly.
+type Interest struct {
+	// Tiers are marginal: each tier's annual rate applies to the part of
+	// the balance between its From and the next tier's From.
+	Tiers []InterestTier `yaml:"tiers"`
+	// Interval is how often the scheduler accrues completed days and posts
+	// completed months.
+	Interval time.Duration `yaml:"interval" env:"INTEREST_INTERVAL"`
+}
+
+type InterestTier struct {
+	From float64 `yaml:"from"`
+	// Rate is the annual rate as a fraction, e.g. 0.015 for 1.5%.
+	Rate float64 `yaml:"rate"`
+}
+
+// Crypto holds the price table used to value asset holdings.
+type Crypto struct {
+	// DefaultCurrency is used when a valuation does not ask for one.
+	DefaultCurrency string `yaml:"default_currency" env:"CRYPTO_DEFAULT_CURRENCY"`
+	// Prices maps a fiat currency to the price of one unit of each asset,
+	// as decimal strings to keep their precision, e.g.
+	// {"USD": {"BTC": "65000.00"}}.
+	Prices map[string]map[string]string `yaml:"prices"`
+}
+
+// Transfers tunes the worker executing scheduled transfers.
+type Transfers struct {
+	Interval time.Duration `yaml:"interval" env:"TRANSFERS_INTERVAL"`
+	// BatchSize caps the transfers executed per interval.
+	BatchSize int `yaml:"batch_size" env:"TRANSFERS_BATCH_SIZE"`
+	// A failed run is retried after RetryDelay times the number of failed
+	// attempts, until MaxAttempts is reached and the run is skipped.
+	MaxAttempts int           `yaml:"max_attempts" env:"TRANSFERS_MAX_ATTEMPTS"`
+	RetryDelay  time.Duration `yaml:"retry_delay" env:"TRANSFERS_RETRY_DELAY"`
+}
+
+// Reconcile tunes the job comparing each wallet's cached balance with the
+// sum of its ledger entries.
+type Reconcile struct {
+	Interval time.Duration `yaml:"interval" env:"RECONCILE_INTERVAL"`
+	// Fix records an adjustment entry for every discrepancy found, so that
+	// the ledger explains the cached balance. Leave it off until the cause
+	// of discrepancies is understood.
+	Fix bool `yaml:"fix" env:"RECONCILE_FIX"`
+}
+
+type Auth struct {
+	APIKeys []string `yaml:"api_keys" env:"API_KEYS"`
+}
+
+const (
+	defaultFile = "config.yaml"
+	dotEnvFile  = ".env"
+)
+
+// Default returns the configuration used when nothing else is provided.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:            ":1323",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+			SwaggerEnabled:  true,
+			TLS: TLS{
+				ReloadInterval: time.Minute,
+			},
+		},
+		Database: Database{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+			AutoMigrate:     true,
+		},
+		Log: Log{
+			Level:  "info",
+			Redact: "all",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "wallet-api",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Enabled:       true,
+			Backend:       "memory",
+			DefaultRate:   20,
+			DefaultBurst:  40,
+			MutatingRate:  2,
+			MutatingBurst: 10,
+			Routes: map[string]RateLimitRule{
+				"POST /api/v1/wallets": {Rate: 0.2, Burst: 5},
+			},
+		},
+		CORS: CORS{
+			AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowHeaders:  []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "X-User-ID"},
+			ExposeHeaders: []string{"X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+			MaxAge:        10 * time.Minute,
+		},
+		Security: Security{
+			HSTSMaxAge: 31536000,
+		},
+		Billing: Billing{
+			StatementDay:       1,
+			PaymentDueDays:     21,
+			MinimumPaymentRate: 0.02,
+			MinimumPayment:     25,
+			LateFee:            25,
+			Interval:           time.Hour,
+		},
+		Interest: Interest{
+			Tiers: []InterestTier{
+				{From: 0, Rate: 0.01},
+				{From: 10000, Rate: 0.015},
+				{From: 50000, Rate: 0.02},
+			},
+			Interval: time.Hour,
+		},
+		Crypto: Crypto{
+			DefaultCurrency: "USD",
+			Prices:          map[string]map[string]string{},
+		},
+		Transfers: Transfers{
+			Interval:    time.Minute,
+			BatchSize:   100,
+			MaxAttempts: 3,
+			RetryDelay:  15 * time.Minute,
+		},
+		Reconcile: Reconcile{
+			Interval: 24 * time.Hour,
+		},
+		Features: map[string]bool{},
+	}
+}
+
+// Load builds the configuration from all sources and validates it.
+func Load() (Config, error) {
+	cfg := Default()
+
+	// .env is read first so that it can name the config file too.
+	dotEnv, err := godotenv.Read(dotEnvFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Config{}, fmt.Errorf("read %s: %w", dotEnvFile, err)
+	}
+	lookup := func(key string) (string, bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		v, ok := dotEnv[key]
+		return v, ok
+	}
+
+	path, explicit := lookup("CONFIG_FILE")
+	if !explicit {
+		path = defaultFile
+	}
+	var problems []string
+	if err := loadFile(&cfg, path, explicit, &problems); err != nil {
+		return Config{}, err
+	}
+
+	applyEnv(reflect.ValueOf(&cfg).Elem(), lookup, &problems)
+	problems = append(problems, cfg.problems()...)
+	if len(problems) > 0 {
+		return Config{}, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// loadFile reads the config file, if any, into cfg. A file that cannot be
+// parsed is reported with the other problems rather than on its own.
+func loadFile(cfg *Config, path string, required bool, problems *[]string) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if err := decodeFile(cfg, path, b); err != nil {
+		*problems = append(*problems, fmt.Sprintf("parse config file %s: %v", path, err))
+	}
+	return nil
+}
+
+// decodeFile decodes a .toml file as TOML and anything else as YAML. TOML
+// is converted to YAML first, so that both formats share the yaml struct
+// tags and duration syntax.
+func decodeFile(cfg *Config, path string, b []byte) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		var doc map[string]any
+		if err := toml.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		var err error
+		if b, err = yaml.Marshal(doc); err != nil {
+			return err
+		}
+	}
+	return yaml.Unmarshal(b, cfg)
+}
+
+// ValidationError lists every invalid setting found while loading.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate reports every problem with cfg at once.
+func (c Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (c Config) problems() []string {
+	var p []string
+	if c.Server.Addr == "" {
+		p = append(p, "server.addr (LISTEN_ADDR) must not be empty")
+	}
+	if c.Server.ReadTimeout <= 0 {
+		p = append(p, "server.read_timeout (READ_TIMEOUT) must be positive")
+	}
+	if c.Server.WriteTimeout <= 0 {
+		p = append(p, "server.write_timeout (WRITE_TIMEOUT) must be positive")
+	}
+	if c.Server.IdleTimeout <= 0 {
+		p = append(p, "server.idle_timeout (IDLE_TIMEOUT) must be positive")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		p = append(p, "server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive")
+	}
+	if c.Server.TLS.Enabled() && (c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "") {
+		p = append(p, "server.tls.cert_file (TLS_CERT_FILE) and server.tls.key_file (TLS_KEY_FILE) must be set together")
+	}
+	if c.Server.TLS.Enabled() && c.Server.TLS.ReloadInterval <= 0 {
+		p = append(p, "server.tls.reload_interval (TLS_RELOAD_INTERVAL) must be positive")
+	}
+	if c.Database.URL == "" {
+		p = append(p, "database.url (DATABASE_URL) is required")
+	}
+	if c.Database.MaxOpenConns < 0 {
+		p = append(p, "database.max_open_conns (DB_MAX_OPEN_CONNS) must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		p = append(p, "database.max_idle_conns (DB_MAX_IDLE_CONNS) must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		p = append(p, "database.max_idle_conns (DB_MAX_IDLE_CONNS) must not exceed database.max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		p = append(p, "database.conn_max_lifetime (DB_CONN_MAX_LIFETIME) must not be negative")
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		p = append(p, "database.conn_max_idle_time (DB_CONN_MAX_IDLE_TIME) must not be negative")
+	}
+	if c.Database.QueryTimeout <= 0 {
+		p = append(p, "database.query_timeout (DB_QUERY_TIMEOUT) must be positive")
+	}
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		p = append(p, fmt.Sprintf("log.level (LOG_LEVEL) must be one of debug, info, warn, error; got %q", c.Log.Level))
+	}
+	switch c.Log.Redact {
+	case "none", "pii", "all":
+	default:
+		p = append(p, fmt.Sprintf("log.redact (LOG_REDACT) must be one of none, pii, all; got %q", c.Log.Redact))
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint == "" {
+			p = append(p, "tracing.endpoint (TRACING_ENDPOINT) is required for the otlp exporter")
+		}
+	default:
+		p = append(p, fmt.Sprintf("tracing.exporter (TRACING_EXPORTER) must be one of none, stdout, otlp; got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p = append(p, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1")
+	}
+	switch c.RateLimit.Backend {
+	case "memory", "postgres":
+	default:
+		p = append(p, fmt.Sprintf("rate_limit.backend (RATE_LIMIT_BACKEND) must be one of memory, postgres; got %q", c.RateLimit.Backend))
+	}
+	if c.RateLimit.DefaultRate <= 0 || c.RateLimit.DefaultBurst < 1 {
+		p = append(p, "rate_limit.default_rate and default_burst (RATE_LIMIT_DEFAULT_*) must be positive")
+	}
+	if c.RateLimit.MutatingRate <= 0 || c.RateLimit.MutatingBurst < 1 {
+		p = append(p, "rate_limit.mutating_rate and mutating_burst (RATE_LIMIT_MUTATING_*) must be positive")
+	}
+	for route, r := range c.RateLimit.Routes {
+		if r.Rate <= 0 || r.Burst < 1 {
+			p = append(p, fmt.Sprintf("rate_limit.routes[%q] rate and burst must be positive", route))
+		}
+		if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			p = append(p, fmt.Sprintf("rate_limit.routes[%q] must look like \"POST /api/v1/wallets\"", route))
+		}
+	}
+	for _, o := range c.CORS.AllowOrigins {
+		if o == "*" && c.CORS.AllowCredentials {
+			p = append(p, "cors.allow_origins (CORS_ALLOW_ORIGINS) must list origins explicitly when allow_credentials is set")
+		}
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		p = append(p, "security.hsts_max_age (HSTS_MAX_AGE) must not be negative")
+	}
+	for i, cidr := range c.Security.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			p = append(p, fmt.Sprintf("security.trusted_proxies[%d] (TRUSTED_PROXIES) must be a CIDR range; got %q", i, cidr))
+		}
+	}
+	if c.Billing.StatementDay < 1 || c.Billing.StatementDay > 28 {
+		p = append(p, "billing.statement_day (BILLING_STATEMENT_DAY) must be between 1 and 28")
+	}
+	if c.Billing.PaymentDueDays < 1 {
+		p = append(p, "billing.payment_due_days (BILLING_PAYMENT_DUE_DAYS) must be positive")
+	}
+	if c.Billing.MinimumPaymentRate <= 0 || c.Billing.MinimumPaymentRate > 1 {
+		p = append(p, "billing.minimum_payment_rate (BILLING_MINIMUM_PAYMENT_RATE) must be greater than 0 and at most 1")
+	}
+	if c.Billing.MinimumPayment < 0 || c.Billing.LateFee < 0 {
+		p = append(p, "billing.minimum_payment and late_fee (BILLING_*) must not be negative")
+	}
+	if c.Billing.Interval <= 0 {
+		p = append(p, "billing.interval (BILLING_INTERVAL) must be positive")
+	}
+	for i, t := range c.Interest.Tiers {
+		if i == 0 && t.From != 0 {
+			p = append(p, "interest.tiers[0].from must be 0")
+		}
+		if i > 0 && t.From <= c.Interest.Tiers[i-1].From {
+			p = append(p, fmt.Sprintf("interest.tiers[%d].from must be greater than the previous tier's", i))
+		}
+		if t.Rate < 0 || t.Rate > 1 {
+			p = append(p, fmt.Sprintf("interest.tiers[%d].rate must be between 0 and 1", i))
+		}
+	}
+	if c.Interest.Interval <= 0 {
+		p = append(p, "interest.interval (INTEREST_INTERVAL) must be positive")
+	}
+	if c.Crypto.DefaultCurrency == "" {
+		p = append(p, "crypto.default_currency (CRYPTO_DEFAULT_CURRENCY) is required")
+	}
+	for currency, prices := range c.Crypto.Prices {
+		for asset, price := range prices {
+			if r, ok := new(big.Rat).SetString(price); !ok || r.Sign() < 0 {
+				p = append(p, fmt.Sprintf("crypto.prices[%q][%q] must be a non-negative decimal; got %q", currency, asset, price))
+			}
+		}
+	}
+	if c.Transfers.Interval <= 0 || c.Transfers.RetryDelay <= 0 {
+		p = append(p, "transfers.interval and retry_delay (TRANSFERS_*) must be positive")
+	}
+	if c.Transfers.BatchSize < 1 || c.Transfers.MaxAttempts < 1 {
+		p = append(p, "transfers.batch_size and max_attempts (TRANSFERS_*) must be at least 1")
+	}
+	if c.Reconcile.Interval <= 0 {
+		p = append(p, "reconcile.interval (RECONCILE_INTERVAL) must be positive")
+	}
+	for i, k := range c.Auth.APIKeys {
+		if strings.TrimSpace(k) == "" {
+			p = append(p, fmt.Sprintf("auth.api_keys[%d] (API_KEYS) must not be blank", i))
+		}
+	}
+	return p
+}
+
+// Enabled reports whether the named feature flag is switched on.
+func (c Config) Enabled(feature string) bool {
+	return c.Features[feature]
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides every field tagged with `env` that has a value in lookup.
+// Parse failures are appended to problems rather than returned so that the
+// caller can report them together with validation errors.
+func applyEnv(v reflect.Value, lookup func(string) (string, bool), problems *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		key := f.Tag.Get("env")
+		if key == "" {
+			if fv.Kind() == reflect.Struct {
+				applyEnv(fv, lookup, problems)
+			}
+			continue
+		}
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setField(fv, raw); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+}
+
+func setField(fv reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		fv.SetInt(int64(d))
+	case fv.Kind() == reflect.String:
+		fv.SetString(raw)
+	case fv.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		fv.SetInt(int64(n))
+	case fv.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		fv.SetFloat(n)
+	case fv.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		fv.SetBool(b)
+	case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				items = append(items, s)
+			}
+		}
+		fv.Set(reflect.ValueOf(items))
+	case fv.Kind() == reflect.Map && fv.Type().Elem().Kind() == reflect.Bool:
+		m := reflect.MakeMap(fv.Type())
+		iter := fv.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		for _, s := range strings.Split(raw, ",") {
+			name, val, found := strings.Cut(strings.TrimSpace(s), "=")
+			if name == "" {
+				continue
+			}
+			on := true
+			if found {
+				b, err := strconv.ParseBool(val)
+				if err != nil {
+					return fmt.Errorf("invalid flag value %q for %q", val, name)
+				}
+				on = b
+			}
+			m.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(on))
+		}
+		fv.Set(m)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
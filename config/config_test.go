@@ -0,0 +1,194 @@
+//go:build unit
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("given only DATABASE_URL should fall back to defaults", func(t *testing.T) {
+		chdirTemp(t)
+		t.Setenv("DATABASE_URL", "postgres://env")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Server.Addr != ":1323" {
+			t.Errorf("expected default addr :1323 but got %q", cfg.Server.Addr)
+		}
+		if cfg.Database.URL != "postgres://env" {
+			t.Errorf("expected database url from env but got %q", cfg.Database.URL)
+		}
+	})
+
+	t.Run("given file, .env and environment should apply precedence", func(t *testing.T) {
+		dir := chdirTemp(t)
+		writeFile(t, filepath.Join(dir, "config.yaml"), `
+server:
+  addr: ":8080"
+  read_timeout: 3s
+database:
+  url: postgres://file
+  query_timeout: 10s
+features:
+  transfers: true
+  statements: true
+`)
+		writeFile(t, filepath.Join(dir, ".env"), "DATABASE_URL=postgres://dotenv\nDB_QUERY_TIMEOUT=20s\n")
+		t.Setenv("DB_QUERY_TIMEOUT", "30s")
+		t.Setenv("FEATURES", "statements=false")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Server.Addr != ":8080" || cfg.Server.ReadTimeout != 3*time.Second {
+			t.Errorf("expected server settings from file but got %+v", cfg.Server)
+		}
+		if cfg.Database.URL != "postgres://dotenv" {
+			t.Errorf("expected .env to override file but got %q", cfg.Database.URL)
+		}
+		if cfg.Database.QueryTimeout != 30*time.Second {
+			t.Errorf("expected environment to override .env but got %s", cfg.Database.QueryTimeout)
+		}
+		if !cfg.Enabled("transfers") || cfg.Enabled("statements") {
+			t.Errorf("expected features to be merged but got %v", cfg.Features)
+		}
+	})
+
+	t.Run("given invalid settings should report all of them", func(t *testing.T) {
+		chdirTemp(t)
+		t.Setenv("READ_TIMEOUT", "soon")
+		t.Setenv("LOG_LEVEL", "loud")
+		t.Setenv("DB_QUERY_TIMEOUT", "0s")
+
+		_, err := Load()
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ValidationError but got %v", err)
+		}
+		for _, want := range []string{"READ_TIMEOUT", "LOG_LEVEL", "DB_QUERY_TIMEOUT", "DATABASE_URL"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to mention %s but got:\n%s", want, err)
+			}
+		}
+	})
+
+	t.Run("given missing explicit config file should fail", func(t *testing.T) {
+		chdirTemp(t)
+		t.Setenv("CONFIG_FILE", "missing.yaml")
+		t.Setenv("DATABASE_URL", "postgres://env")
+
+		if _, err := Load(); err == nil {
+			t.Error("expected error for missing config file")
+		}
+	})
+
+	t.Run("given invalid pool settings should report them", func(t *testing.T) {
+		chdirTemp(t)
+		t.Setenv("DATABASE_URL", "postgres://env")
+		t.Setenv("DB_MAX_OPEN_CONNS", "10")
+		t.Setenv("DB_MAX_IDLE_CONNS", "20")
+		t.Setenv("DB_CONN_MAX_LIFETIME", "-1m")
+
+		_, err := Load()
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ValidationError but got %v", err)
+		}
+		for _, want := range []string{"DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to mention %s but got:\n%s", want, err)
+			}
+		}
+	})
+
+	t.Run("given CONFIG_FILE in .env should load that file", func(t *testing.T) {
+		dir := chdirTemp(t)
+		writeFile(t, filepath.Join(dir, "other.yaml"), "server:\n  addr: \":9090\"\n")
+		writeFile(t, filepath.Join(dir, ".env"), "CONFIG_FILE=other.yaml\nDATABASE_URL=postgres://dotenv\n")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Server.Addr != ":9090" {
+			t.Errorf("expected addr from the file named in .env but got %q", cfg.Server.Addr)
+		}
+	})
+
+	t.Run("given TOML config file should load it", func(t *testing.T) {
+		dir := chdirTemp(t)
+		writeFile(t, filepath.Join(dir, "config.toml"), `
+[server]
+addr = ":8080"
+read_timeout = "3s"
+
+[database]
+url = "postgres://toml"
+
+[features]
+transfers = true
+`)
+		t.Setenv("CONFIG_FILE", "config.toml")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Server.Addr != ":8080" || cfg.Server.ReadTimeout != 3*time.Second {
+			t.Errorf("expected server settings from file but got %+v", cfg.Server)
+		}
+		if cfg.Database.URL != "postgres://toml" || !cfg.Enabled("transfers") {
+			t.Errorf("expected database and features from file but got %+v %v", cfg.Database, cfg.Features)
+		}
+	})
+
+	t.Run("given unparsable config file should report it with other problems", func(t *testing.T) {
+		dir := chdirTemp(t)
+		writeFile(t, filepath.Join(dir, "config.yaml"), "server: [unclosed\n")
+		t.Setenv("LOG_LEVEL", "loud")
+
+		_, err := Load()
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ValidationError but got %v", err)
+		}
+		for _, want := range []string{"parse config file config.yaml", "LOG_LEVEL", "DATABASE_URL"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to mention %s but got:\n%s", want, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Check is a single readiness dependency, e.g. the database.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Handler struct {
+	checks       []Check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func New(timeout time.Duration, checks ...Check) *Handler {
+	return &Handler{checks: checks, timeout: timeout}
+}
+
+// SetShuttingDown makes readiness fail so the orchestrator stops routing
+// new traffic while in-flight requests drain.
+func (h *Handler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type Status struct {
+	Status string                 `json:"status" example:"ok"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type CheckResult struct {
+	Status    string  `json:"status" example:"ok"`
+	LatencyMs float64 `json:"latency_ms" example:"1.2"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// LivenessHandler
+//
+//	@Summary		Liveness probe
+//	@Description	Report that the process is running
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Status
+//	@Router			/healthz [get]
+func (h *Handler) LivenessHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, Status{Status: StatusOK})
+}
+
+// ReadinessHandler
+//
+//	@Summary		Readiness probe
+//	@Description	Report whether the service can serve traffic, with per-check details
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	Status
+//	@Failure		503	{object}	Status
+//	@Router			/readyz [get]
+func (h *Handler) ReadinessHandler(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	results := make(map[string]CheckResult, len(h.checks)+1)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			start := time.Now()
+			err := check.Run(ctx)
+			r := CheckResult{
+				Status:    StatusOK,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				r.Status, r.Error = StatusFail, err.Error()
+			}
+			mu.Lock()
+			results[check.Name] = r
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		results["shutdown"] = CheckResult{Status: StatusFail, Error: "server is shutting down"}
+	}
+
+	status, code := StatusOK, http.StatusOK
+	for _, r := range results {
+		if r.Status != StatusOK {
+			status, code = StatusFail, http.StatusServiceUnavailable
+			break
+		}
+	}
+	return c.JSON(code, Status{Status: status, Checks: results})
+}
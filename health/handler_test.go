@@ -0,0 +1,74 @@
+//go:build unit
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func readiness(t *testing.T, h *Handler) (int, Status) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h.ReadinessHandler(c)
+
+	var got Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unable to unmarshal json: %v", err)
+	}
+	return rec.Code, got
+}
+
+func ok(ctx context.Context) error { return nil }
+
+func TestReadiness(t *testing.T) {
+	t.Run("given all checks pass should return 200 with per-check details", func(t *testing.T) {
+		h := New(time.Second, Check{Name: "database", Run: ok}, Check{Name: "migrations", Run: ok})
+
+		code, got := readiness(t, h)
+
+		if code != http.StatusOK || got.Status != StatusOK {
+			t.Errorf("expected ready but got %d %+v", code, got)
+		}
+		if len(got.Checks) != 2 || got.Checks["database"].Status != StatusOK {
+			t.Errorf("expected both checks reported but got %+v", got.Checks)
+		}
+	})
+
+	t.Run("given failing check should return 503 and its error", func(t *testing.T) {
+		h := New(time.Second, Check{Name: "database", Run: func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}})
+
+		code, got := readiness(t, h)
+
+		if code != http.StatusServiceUnavailable || got.Status != StatusFail {
+			t.Errorf("expected not ready but got %d %+v", code, got)
+		}
+		if got.Checks["database"].Error != "connection refused" {
+			t.Errorf("expected check error but got %+v", got.Checks["database"])
+		}
+	})
+
+	t.Run("given shutting down should return 503", func(t *testing.T) {
+		h := New(time.Second, Check{Name: "database", Run: ok})
+		h.SetShuttingDown()
+
+		code, got := readiness(t, h)
+
+		if code != http.StatusServiceUnavailable || got.Checks["shutdown"].Status != StatusFail {
+			t.Errorf("expected not ready during shutdown but got %d %+v", code, got)
+		}
+	})
+}
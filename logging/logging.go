@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+// Redaction levels accepted by config.Log.Redact.
+const (
+	RedactNone = "none"
+	RedactPII  = "pii"
+	RedactAll  = "all"
+)
+
+const redacted = "[REDACTED]"
+
+// piiKeys and amountKeys are attribute keys whose values are masked
+// depending on the configured redaction level.
+var (
+	piiKeys    = map[string]bool{"user_name": true, "wallet_name": true}
+	amountKeys = map[string]bool{"balance": true, "amount": true, "available_balance": true}
+)
+
+// New returns a JSON logger writing to w. Every record logged with a
+// context carrying a request ID gets a request_id attribute.
+func New(w io.Writer, cfg config.Log) *slog.Logger {
+	var level slog.Level
+	level.UnmarshalText([]byte(cfg.Level))
+
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactor(cfg.Redact),
+	})
+	return slog.New(contextHandler{h})
+}
+
+func redactor(level string) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		switch {
+		case level == RedactNone:
+		case piiKeys[a.Key]:
+			a.Value = slog.StringValue(redacted)
+		case level == RedactAll && amountKeys[a.Key]:
+			a.Value = slog.StringValue(redacted)
+		}
+		return a
+	}
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+const maxRequestIDLen = 128
+
+// RequestIDMiddleware accepts an incoming X-Request-ID header or generates
+// one, echoes it back on the response and stores it in the request context
+// so it reaches handlers and store calls.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := strings.TrimSpace(req.Header.Get(echo.HeaderXRequestID))
+			if id == "" || len(id) > maxRequestIDLen {
+				id = newRequestID()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			c.SetRequest(req.WithContext(WithRequestID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Middleware logs one record per request.
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			req, res := c.Request(), c.Response()
+			level := slog.LevelInfo
+			if res.Status >= 500 {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+				slog.String("uri", req.RequestURI),
+				slog.Int("status", res.Status),
+				slog.Int64("bytes_out", res.Size),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("remote_ip", c.RealIP()),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogAttrs(req.Context(), level, "request", attrs...)
+			return err
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build unit
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	serve := func(header string) (string, string) {
+		e := echo.New()
+		e.Use(RequestIDMiddleware())
+		var seen string
+		e.GET("/", func(c echo.Context) error {
+			seen = RequestID(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(echo.HeaderXRequestID, header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return seen, rec.Header().Get(echo.HeaderXRequestID)
+	}
+
+	t.Run("given incoming X-Request-ID should reuse it", func(t *testing.T) {
+		seen, echoed := serve("abc-123")
+
+		if seen != "abc-123" || echoed != "abc-123" {
+			t.Errorf("expected abc-123 in context and response but got %q and %q", seen, echoed)
+		}
+	})
+
+	t.Run("given no X-Request-ID should generate one", func(t *testing.T) {
+		seen, echoed := serve("")
+
+		if seen == "" || seen != echoed {
+			t.Errorf("expected generated id in context and response but got %q and %q", seen, echoed)
+		}
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	log := func(redact string) map[string]any {
+		var buf bytes.Buffer
+		logger := New(&buf, config.Log{Level: "info", Redact: redact})
+		ctx := WithRequestID(context.Background(), "req-1")
+		logger.InfoContext(ctx, "wallet created", "user_name", "John Doe", "balance", 100.0, "wallet_id", 1)
+
+		var got map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unable to unmarshal log line: %v", err)
+		}
+		return got
+	}
+
+	tests := []struct {
+		redact      string
+		wantName    any
+		wantBalance any
+	}{
+		{RedactNone, "John Doe", 100.0},
+		{RedactPII, redacted, 100.0},
+		{RedactAll, redacted, redacted},
+	}
+	for _, tt := range tests {
+		t.Run("given redact "+tt.redact, func(t *testing.T) {
+			got := log(tt.redact)
+
+			if got["user_name"] != tt.wantName || got["balance"] != tt.wantBalance {
+				t.Errorf("expected user_name %v and balance %v but got %v", tt.wantName, tt.wantBalance, got)
+			}
+			if got["request_id"] != "req-1" || got["wallet_id"] != 1.0 {
+				t.Errorf("expected request_id and wallet_id untouched but got %v", got)
+			}
+		})
+	}
+}
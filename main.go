@@ -1,12 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/openmymai/fun-exercise-api/admin"
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/health"
+	"github.com/openmymai/fun-exercise-api/logging"
+	"github.com/openmymai/fun-exercise-api/metrics"
 	"github.com/openmymai/fun-exercise-api/postgres"
+	"github.com/openmymai/fun-exercise-api/ratelimit"
+	"github.com/openmymai/fun-exercise-api/security"
+	"github.com/openmymai/fun-exercise-api/tracing"
 	"github.com/openmymai/fun-exercise-api/wallet"
+	"github.com/openmymai/fun-exercise-api/worker"
 
 	_ "github.com/openmymai/fun-exercise-api/docs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -15,27 +37,246 @@ import (
 // @description	Sophisticated Wallet API
 // @host			localhost:1323
 func main() {
-	p, err := postgres.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := logging.New(os.Stdout, cfg.Log)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("tracing setup failed", err)
+	}
+
+	p, err := postgres.New(cfg.Database)
 	if err != nil {
-		panic(err)
+		fatal("database connection failed", err)
 	}
+	if cfg.Database.AutoMigrate {
+		if err := p.Migrate(context.Background()); err != nil {
+			fatal("database migration failed", err)
+		}
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(p.Db, "wallet"),
+		metrics.NewWalletCollector(p, cfg.Database.QueryTimeout),
+	)
+	p.Observer = metrics.NewStoreObserver(reg)
+	p.Interest = cfg.Interest
+	p.Crypto = cfg.Crypto
 
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.HideBanner = true
+	e.HidePort = true
+	e.IPExtractor = security.IPExtractor(cfg.Security)
+	e.Use(logging.RequestIDMiddleware())
+	e.Use(logging.Middleware(logger))
 	e.Use(middleware.Recover())
+	e.Use(tracing.Middleware())
+	e.Use(metrics.Middleware(reg))
+	e.Use(security.Headers(cfg.Security))
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		e.Use(security.CORS(cfg.CORS))
+	}
+
+	e.GET("/metrics", metrics.Handler(reg))
 
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	if cfg.Server.SwaggerEnabled {
+		e.GET("/swagger/*", echoSwagger.WrapHandler, security.ContentSecurityPolicy(security.SwaggerCSP))
+	}
+
+	healthHandler := health.New(cfg.Database.QueryTimeout,
+		health.Check{Name: "database", Run: p.Ping},
+		health.Check{Name: "migrations", Run: p.CheckSchemaVersion},
+	)
+	e.GET("/healthz", healthHandler.LivenessHandler)
+	e.GET("/readyz", healthHandler.ReadinessHandler)
+
+	workers := worker.New()
 
 	handler := wallet.New(p)
 	v1 := e.Group("/api/v1")
+	if cfg.RateLimit.Enabled {
+		var backend ratelimit.Backend = ratelimit.NewMemory()
+		if cfg.RateLimit.Backend == "postgres" {
+			backend = p
+		}
+		v1.Use(ratelimit.New(backend, cfg.RateLimit, cfg.Auth.APIKeys).Middleware())
+		workers.Every("ratelimit-prune", time.Minute, func(ctx context.Context) error {
+			return backend.PruneBuckets(ctx, time.Now().Add(-time.Hour))
+		})
+	}
 	{
 		v1.GET("/wallets", handler.WalletsHandler)
 		v1.GET("/users/:id/wallets", handler.WalletsByUserHandler)
+		v1.GET("/users/:id/summary", handler.UserSummaryHandler)
 		v1.GET("/wallets/wallet", handler.WalletsTypeQueryHandler)
 		v1.POST("/wallets", handler.CreateWalletHandler)
 		v1.PUT("/wallets/:id", handler.UpdateWalletHandler)
 		v1.DELETE("/users/:id/wallets", handler.DeleteWalletHandler)
+		v1.POST("/wallets/:id/deposits", handler.DepositHandler)
+		v1.POST("/wallets/:id/withdrawals", handler.WithdrawalHandler)
+		v1.GET("/wallets/:id/balance", handler.BalanceHandler)
+		v1.GET("/wallets/:id/balance/series", handler.BalanceSeriesHandler)
+		v1.GET("/wallets/:id/statements", handler.StatementsHandler)
+		v1.GET("/wallets/:id/statements/:statement_id", handler.StatementHandler)
+		v1.GET("/wallets/:id/interest/preview", handler.InterestPreviewHandler)
+		v1.GET("/wallets/:id/holdings", handler.HoldingsHandler)
+		v1.POST("/wallets/:id/holdings", handler.AddHoldingHandler)
+		v1.DELETE("/wallets/:id/holdings/:asset", handler.RemoveHoldingHandler)
+		v1.GET("/wallets/:id/valuation", handler.ValuationHandler)
+		v1.GET("/wallets/:id/holds", handler.HoldsHandler)
+		v1.POST("/wallets/:id/holds", handler.PlaceHoldHandler)
+		v1.POST("/wallets/:id/holds/:hold_id/capture", handler.CaptureHoldHandler)
+		v1.POST("/wallets/:id/holds/:hold_id/void", handler.VoidHoldHandler)
+		v1.GET("/wallets/:id/spending", handler.WalletSpendingHandler)
+		v1.GET("/users/:id/spending", handler.UserSpendingHandler)
+		v1.GET("/categories", handler.CategoriesHandler)
+		v1.GET("/wallets/:id/goals", handler.GoalsHandler)
+		v1.POST("/wallets/:id/goals", handler.CreateGoalHandler)
+		v1.GET("/wallets/:id/goals/:goal_id", handler.GoalHandler)
+		v1.DELETE("/wallets/:id/goals/:goal_id", handler.DeleteGoalHandler)
+		v1.GET("/wallets/:id/budgets", handler.WalletBudgetsHandler)
+		v1.GET("/users/:id/budgets", handler.UserBudgetsHandler)
+		v1.POST("/budgets", handler.CreateBudgetHandler)
+		v1.GET("/budgets/:id", handler.BudgetHandler)
+		v1.DELETE("/budgets/:id", handler.DeleteBudgetHandler)
+		v1.GET("/budgets/:id/alerts", handler.BudgetAlertsHandler)
+		v1.POST("/transfers", handler.CreateTransferHandler)
+		v1.GET("/transfers/:id", handler.TransferHandler)
+		v1.POST("/transfers/:id/reverse", handler.ReverseTransferHandler)
+		v1.POST("/transfers/:id/refunds", handler.RefundTransferHandler)
+		v1.GET("/scheduled-transfers", handler.ScheduledTransfersHandler)
+		v1.POST("/scheduled-transfers", handler.CreateScheduledTransferHandler)
+		v1.GET("/scheduled-transfers/:id", handler.ScheduledTransferHandler)
+		v1.GET("/scheduled-transfers/:id/runs", handler.ScheduledRunsHandler)
+		v1.POST("/scheduled-transfers/:id/pause", handler.PauseScheduledTransferHandler)
+		v1.POST("/scheduled-transfers/:id/resume", handler.ResumeScheduledTransferHandler)
+		v1.POST("/scheduled-transfers/:id/cancel", handler.CancelScheduledTransferHandler)
 	}
 
-	e.Logger.Fatal(e.Start(":1323"))
+	workers.Every("billing", cfg.Billing.Interval, func(ctx context.Context) error {
+		now := time.Now()
+		if _, err := p.GenerateStatements(ctx, now, cfg.Billing); err != nil {
+			return err
+		}
+		_, err := p.PostLateFees(ctx, now, cfg.Billing)
+		return err
+	})
+	workers.Every("hold-expiry", time.Minute, func(ctx context.Context) error {
+		_, err := p.ExpireHolds(ctx, time.Now())
+		return err
+	})
+	workers.Every("balance-snapshots", time.Hour, func(ctx context.Context) error {
+		_, err := p.SnapshotBalances(ctx, time.Now())
+		return err
+	})
+	workers.Every("scheduled-transfers", cfg.Transfers.Interval, func(ctx context.Context) error {
+		_, err := p.RunScheduledTransfers(ctx, time.Now(), cfg.Transfers)
+		return err
+	})
+	// Reconciliation runs daily by default, so it also runs at start rather
+	// than a full interval after every deploy.
+	workers.EveryNow("reconcile", cfg.Reconcile.Interval, func(ctx context.Context) error {
+		_, err := p.Reconcile(ctx, cfg.Reconcile.Fix)
+		return err
+	})
+	workers.Every("interest", cfg.Interest.Interval, func(ctx context.Context) error {
+		now := time.Now()
+		if _, err := p.AccrueInterest(ctx, now); err != nil {
+			return err
+		}
+		_, err := p.PostInterest(ctx, now)
+		return err
+	})
+
+	// The admin API changes balances and reference data, so it is only
+	// served behind API keys.
+	if len(cfg.Auth.APIKeys) > 0 {
+		adminHandler := admin.New(p)
+		a := v1.Group("/admin", middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+			KeyLookup: "header:X-API-Key",
+			Validator: func(key string, c echo.Context) (bool, error) {
+				return slices.Contains(cfg.Auth.APIKeys, key), nil
+			},
+		}))
+		a.GET("/db/stats", adminHandler.DBStatsHandler)
+		a.GET("/wallet-types", handler.WalletTypesHandler)
+		a.POST("/wallet-types", handler.CreateWalletTypeHandler)
+		a.PUT("/wallet-types/:name", handler.UpdateWalletTypeHandler)
+		a.DELETE("/wallet-types/:name", handler.RetireWalletTypeHandler)
+		a.POST("/categories", handler.CreateCategoryHandler)
+		a.PUT("/categories/:name", handler.UpdateCategoryHandler)
+		a.DELETE("/categories/:name", handler.DeleteCategoryHandler)
+		a.GET("/category-rules", handler.CategoryRulesHandler)
+		a.POST("/category-rules", handler.CreateCategoryRuleHandler)
+		a.DELETE("/category-rules/:id", handler.DeleteCategoryRuleHandler)
+		a.GET("/ledger/check", handler.LedgerCheckHandler)
+		a.GET("/ledger/accounts", handler.LedgerAccountsHandler)
+		a.GET("/ledger/entries/:id", handler.JournalEntryHandler)
+		a.GET("/reconciliation/reports", handler.ReconciliationReportsHandler)
+		a.POST("/reconciliation/reports", handler.ReconcileHandler)
+		a.GET("/reconciliation/reports/:id", handler.ReconciliationReportHandler)
+	} else {
+		slog.Warn("admin API disabled: no API keys configured (auth.api_keys, API_KEYS)")
+	}
+
+	e.Server = &http.Server{
+		Addr:         cfg.Server.Addr,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	if cfg.Server.TLS.Enabled() {
+		certs, err := security.NewCertReloader(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+		if err != nil {
+			fatal("tls setup failed", err)
+		}
+		e.Server.TLSConfig = certs.TLSConfig()
+		workers.Every("tls-reload", cfg.Server.TLS.ReloadInterval, func(ctx context.Context) error {
+			return certs.Reload()
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("server started", "addr", cfg.Server.Addr, "tls", cfg.Server.TLS.Enabled())
+		if err := e.StartServer(e.Server); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("server failed", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("shutting down")
+	healthHandler.SetShuttingDown()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http server shutdown failed", "error", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		slog.Error("background workers shutdown failed", "error", err)
+	}
+	if err := p.Close(); err != nil {
+		slog.Error("database close failed", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("tracing shutdown failed", "error", err)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "wallet"
+
+// Handler serves the metrics gathered by reg in the Prometheus text format.
+func Handler(reg *prometheus.Registry) echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+}
+
+// Middleware records request counts and latency per route and status.
+// Routes are reported by their registered pattern, e.g. /api/v1/wallets/:id,
+// so that path parameters do not explode the label cardinality.
+func Middleware(reg prometheus.Registerer) echo.MiddlewareFunc {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests processed, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	reg.MustRegister(requests, duration)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			status := strconv.Itoa(c.Response().Status)
+			requests.WithLabelValues(c.Request().Method, route, status).Inc()
+			duration.WithLabelValues(c.Request().Method, route, status).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+// StoreObserver records latency and error counts for each store method. It
+// satisfies postgres.Observer.
+type StoreObserver struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func NewStoreObserver(reg prometheus.Registerer) *StoreObserver {
+	o := &StoreObserver{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "store_query_duration_seconds",
+			Help:      "Store method latency, by method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "store_query_errors_total",
+			Help:      "Store method calls that returned an error, by method.",
+		}, []string{"method"}),
+	}
+	reg.MustRegister(o.duration, o.errors)
+	return o
+}
+
+func (o *StoreObserver) ObserveQuery(method string, elapsed time.Duration, err error) {
+	o.duration.WithLabelValues(method).Observe(elapsed.Seconds())
+	if err != nil {
+		o.errors.WithLabelValues(method).Inc()
+	}
+}
+
+type TypeTotaler interface {
+	WalletTotalsByType(ctx context.Context) ([]wallet.TypeTotal, error)
+}
+
+// WalletCollector reports business gauges computed from the store at
+// scrape time.
+type WalletCollector struct {
+	store   TypeTotaler
+	timeout time.Duration
+	count   *prometheus.Desc
+	balance *prometheus.Desc
+	up      *prometheus.Desc
+}
+
+func NewWalletCollector(store TypeTotaler, timeout time.Duration) *WalletCollector {
+	return &WalletCollector{
+		store:   store,
+		timeout: timeout,
+		count: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "wallets"),
+			"Number of wallets, by wallet type.", []string{"wallet_type"}, nil),
+		balance: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "balance_total"),
+			"Sum of wallet balances, by wallet type.", []string{"wallet_type"}, nil),
+		up: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "business_metrics_up"),
+			"Whether the last business metrics query succeeded.", nil, nil),
+	}
+}
+
+func (w *WalletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- w.count
+	ch <- w.balance
+	ch <- w.up
+}
+
+func (w *WalletCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	totals, err := w.store.WalletTotalsByType(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(w.up, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(w.up, prometheus.GaugeValue, 1)
+	for _, t := range totals {
+		ch <- prometheus.MustNewConstMetric(w.count, prometheus.GaugeValue, float64(t.Count), t.WalletType)
+		ch <- prometheus.MustNewConstMetric(w.balance, prometheus.GaugeValue, t.Balance, t.WalletType)
+	}
+}
@@ -0,0 +1,92 @@
+//go:build unit
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type StubTotals struct {
+	totals []wallet.TypeTotal
+	err    error
+}
+
+func (s StubTotals) WalletTotalsByType(ctx context.Context) ([]wallet.TypeTotal, error) {
+	return s.totals, s.err
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("given request to parameterised route should label by route pattern", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		e := echo.New()
+		e.Use(Middleware(reg))
+		e.GET("/api/v1/users/:id/wallets", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, nil)
+		})
+
+		for _, id := range []string{"1", "2"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+id+"/wallets", nil)
+			e.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		want := `
+# HELP wallet_http_requests_total HTTP requests processed, by method, route and status.
+# TYPE wallet_http_requests_total counter
+wallet_http_requests_total{method="GET",route="/api/v1/users/:id/wallets",status="200"} 2
+`
+		if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "wallet_http_requests_total"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestStoreObserver(t *testing.T) {
+	t.Run("given failed call should count error for method", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		o := NewStoreObserver(reg)
+
+		o.ObserveQuery("Wallets", time.Millisecond, nil)
+		o.ObserveQuery("CreateWallet", time.Millisecond, errors.New("boom"))
+
+		if got := testutil.ToFloat64(o.errors.WithLabelValues("CreateWallet")); got != 1 {
+			t.Errorf("expected 1 error but got %v", got)
+		}
+		if got := testutil.ToFloat64(o.errors.WithLabelValues("Wallets")); got != 0 {
+			t.Errorf("expected 0 errors but got %v", got)
+		}
+	})
+}
+
+func TestWalletCollector(t *testing.T) {
+	t.Run("given totals by type should expose count and balance gauges", func(t *testing.T) {
+		c := NewWalletCollector(StubTotals{totals: []wallet.TypeTotal{
+			{WalletType: "Savings", Count: 2, Balance: 3000},
+			{WalletType: "Credit Card", Count: 2, Balance: 1500},
+		}}, time.Second)
+
+		want := `
+# HELP wallet_balance_total Sum of wallet balances, by wallet type.
+# TYPE wallet_balance_total gauge
+wallet_balance_total{wallet_type="Credit Card"} 1500
+wallet_balance_total{wallet_type="Savings"} 3000
+# HELP wallet_wallets Number of wallets, by wallet type.
+# TYPE wallet_wallets gauge
+wallet_wallets{wallet_type="Credit Card"} 2
+wallet_wallets{wallet_type="Savings"} 2
+`
+		if err := testutil.CollectAndCompare(c, strings.NewReader(want), "wallet_balance_total", "wallet_wallets"); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// snapshotDelay is how long after midnight a day is left open before it is
+// snapshotted, so that transactions started before midnight have
+// committed.
+const snapshotDelay = time.Hour
+
+// balancesAt selects the balance of wallet $1 just before each instant in
+// $2: the nearest snapshot ending by then plus the ledger entries since.
+// Amounts are summed rather than taking the last balance_after, which
+// concurrent writers may commit out of created_at order.
+const balancesAt = `SELECT at, COALESCE(s.balance, 0) + COALESCE((SELECT SUM(t.amount) FROM wallet_transactions t
+		WHERE t.wallet_id = $1 AND t.created_at >= COALESCE(s.snapshot_date + 1, '-infinity'::date) AND t.created_at < at), 0)
+	FROM unnest($2::timestamp[]) at
+	LEFT JOIN LATERAL (SELECT snapshot_date, balance FROM balance_snapshots
+		WHERE wallet_id = $1 AND snapshot_date + 1 <= at ORDER BY snapshot_date DESC LIMIT 1) s ON TRUE
+	ORDER BY at`
+
+func walletCurrency(ctx context.Context, q querier, walletID string) (string, error) {
+	var currency string
+	err := q.QueryRowContext(ctx, "SELECT currency FROM user_wallet WHERE id = $1", walletID).Scan(&currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", wallet.ErrNotFound
+	}
+	return currency, err
+}
+
+func (p *Postgres) balancesAt(ctx context.Context, walletID string, instants []time.Time) ([]float64, error) {
+	rows, err := p.db().QueryContext(ctx, balancesAt, walletID, pq.Array(formatInstants(instants)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make([]float64, 0, len(instants))
+	for rows.Next() {
+		var at time.Time
+		var balance float64
+		if err := rows.Scan(&at, &balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+// formatInstants formats times for a timestamp[] parameter.
+func formatInstants(instants []time.Time) []string {
+	out := make([]string, len(instants))
+	for i, t := range instants {
+		out[i] = t.UTC().Format("2006-01-02 15:04:05.999999")
+	}
+	return out
+}
+
+// BalanceAt returns a wallet's balance just before asOf according to its
+// ledger.
+func (p *Postgres) BalanceAt(ctx context.Context, walletID string, asOf time.Time) (_ wallet.Balance, err error) {
+	defer p.observe("BalanceAt", time.Now(), &err)
+
+	id, err := strconv.Atoi(walletID)
+	if err != nil {
+		return wallet.Balance{}, wallet.ErrNotFound
+	}
+	currency, err := walletCurrency(ctx, p.db(), walletID)
+	if err != nil {
+		return wallet.Balance{}, err
+	}
+	balances, err := p.balancesAt(ctx, walletID, []time.Time{asOf})
+	if err != nil {
+		return wallet.Balance{}, err
+	}
+	return wallet.Balance{WalletID: id, AsOf: asOf, Balance: balances[0], Currency: currency}, nil
+}
+
+// BalanceSeries returns a wallet's closing balance at the end of each
+// period of granularity between the dates from and to.
+func (p *Postgres) BalanceSeries(ctx context.Context, walletID, granularity string, from, to time.Time) (_ wallet.BalanceSeries, err error) {
+	defer p.observe("BalanceSeries", time.Now(), &err)
+
+	id, err := strconv.Atoi(walletID)
+	if err != nil {
+		return wallet.BalanceSeries{}, wallet.ErrNotFound
+	}
+	dates, err := wallet.SeriesDates(granularity, from, to)
+	if err != nil {
+		return wallet.BalanceSeries{}, err
+	}
+	currency, err := walletCurrency(ctx, p.db(), walletID)
+	if err != nil {
+		return wallet.BalanceSeries{}, err
+	}
+	instants := make([]time.Time, len(dates))
+	for i, d := range dates {
+		instants[i] = d.AddDate(0, 0, 1)
+	}
+	balances, err := p.balancesAt(ctx, walletID, instants)
+	if err != nil {
+		return wallet.BalanceSeries{}, err
+	}
+
+	s := wallet.BalanceSeries{WalletID: id, Currency: currency, Granularity: granularity, From: from, To: to, Points: []wallet.BalancePoint{}}
+	for i, d := range dates {
+		s.Points = append(s.Points, wallet.BalancePoint{Date: d, Balance: balances[i]})
+	}
+	return s, nil
+}
+
+// SnapshotBalances records the closing balance of every wallet for each
+// day since its last snapshot, or since it was opened, up to the last day
+// that ended at least snapshotDelay before now. Each day is built on the
+// previous snapshot, so only new ledger entries are read. Running it again
+// is a no-op.
+func (p *Postgres) SnapshotBalances(ctx context.Context, now time.Time) (n int, err error) {
+	defer p.observe("SnapshotBalances", time.Now(), &err)
+
+	last := now.UTC().Add(-snapshotDelay).Truncate(24*time.Hour).AddDate(0, 0, -1)
+	res, err := p.db().ExecContext(ctx, `INSERT INTO balance_snapshots (wallet_id, snapshot_date, balance)
+		SELECT w.id, d::date, COALESCE(prev.balance, 0) + COALESCE((SELECT SUM(t.amount) FROM wallet_transactions t
+			WHERE t.wallet_id = w.id AND t.created_at >= COALESCE(prev.snapshot_date + 1, '-infinity'::date) AND t.created_at < d::date + 1), 0)
+		FROM user_wallet w
+		LEFT JOIN LATERAL (SELECT snapshot_date, balance FROM balance_snapshots
+			WHERE wallet_id = w.id ORDER BY snapshot_date DESC LIMIT 1) prev ON TRUE
+		CROSS JOIN LATERAL generate_series(COALESCE(prev.snapshot_date + 1, w.created_at::date, $1::date), $1::date, interval '1 day') d
+		ON CONFLICT DO NOTHING`, last)
+	if err != nil {
+		return 0, err
+	}
+	inserted, _ := res.RowsAffected()
+	if inserted > 0 {
+		slog.InfoContext(ctx, "balance snapshots taken", "count", inserted, "through", last.Format(time.DateOnly))
+	}
+	return int(inserted), nil
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const budgetColumns = "id, wallet_id, user_id, COALESCE(category, ''), amount, period, created_at"
+
+func scanBudget(row interface{ Scan(...any) error }) (wallet.Budget, error) {
+	var b wallet.Budget
+	var walletID, userID sql.NullInt64
+	err := row.Scan(&b.ID, &walletID, &userID, &b.Category, &b.Amount, &b.Period, &b.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Budget{}, wallet.ErrBudgetNotFound
+	}
+	if walletID.Valid {
+		id := int(walletID.Int64)
+		b.WalletID = &id
+	}
+	if userID.Valid {
+		id := int(userID.Int64)
+		b.UserID = &id
+	}
+	return b, err
+}
+
+// budgetSpent returns what was spent against b from start to the end of
+// that period, according to the ledger.
+func budgetSpent(ctx context.Context, q querier, b wallet.Budget, start time.Time) (float64, error) {
+	var spent float64
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(-SUM(t.amount), 0)
+		FROM wallet_transactions t JOIN user_wallet w ON w.id = t.wallet_id
+		WHERE (w.id = $1 OR w.user_id = $2) AND t.amount < 0 AND t.kind = ANY($3)
+		AND t.created_at >= $4 AND t.created_at < $5 AND ($6::text = '' OR t.category = $6)`,
+		b.WalletID, b.UserID, pq.Array(wallet.SpendingKinds), start, wallet.PeriodEnd(b.Period, start), b.Category).Scan(&spent)
+	return spent, err
+}
+
+func (p *Postgres) budgetStatuses(ctx context.Context, now time.Time, query string, args ...any) ([]wallet.BudgetStatus, error) {
+	rows, err := p.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []wallet.Budget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := []wallet.BudgetStatus{}
+	for _, b := range budgets {
+		start := wallet.PeriodStart(b.Period, now)
+		spent, err := budgetSpent(ctx, p.db(), b, start)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, wallet.NewBudgetStatus(b, start, spent))
+	}
+	return statuses, nil
+}
+
+// WalletBudgets returns the budgets set on a wallet with their consumption
+// in the period containing now.
+func (p *Postgres) WalletBudgets(ctx context.Context, walletID string, now time.Time) (_ []wallet.BudgetStatus, err error) {
+	defer p.observe("WalletBudgets", time.Now(), &err)
+
+	if err = p.walletExists(ctx, walletID); err != nil {
+		return nil, err
+	}
+	return p.budgetStatuses(ctx, now, "SELECT "+budgetColumns+" FROM budgets WHERE wallet_id = $1 ORDER BY id", walletID)
+}
+
+// UserBudgets returns a user's budgets, both across their wallets and on
+// single wallets, with their consumption in the period containing now.
+func (p *Postgres) UserBudgets(ctx context.Context, userID string, now time.Time) (_ []wallet.BudgetStatus, err error) {
+	defer p.observe("UserBudgets", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(userID, 10, 32); err != nil {
+		return nil, wallet.ErrNotFound
+	}
+	return p.budgetStatuses(ctx, now, "SELECT "+budgetColumns+" FROM budgets WHERE user_id = $1 OR wallet_id IN (SELECT id FROM user_wallet WHERE user_id = $1) ORDER BY id", userID)
+}
+
+func (p *Postgres) Budget(ctx context.Context, id string, now time.Time) (_ wallet.BudgetStatus, err error) {
+	defer p.observe("Budget", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.BudgetStatus{}, wallet.ErrBudgetNotFound
+	}
+	b, err := scanBudget(p.db().QueryRowContext(ctx, "SELECT "+budgetColumns+" FROM budgets WHERE id = $1", id))
+	if err != nil {
+		return wallet.BudgetStatus{}, err
+	}
+	start := wallet.PeriodStart(b.Period, now)
+	spent, err := budgetSpent(ctx, p.db(), b, start)
+	if err != nil {
+		return wallet.BudgetStatus{}, err
+	}
+	return wallet.NewBudgetStatus(b, start, spent), nil
+}
+
+func (p *Postgres) CreateBudget(ctx context.Context, b wallet.Budget) (_ wallet.Budget, err error) {
+	defer p.observe("CreateBudget", time.Now(), &err)
+
+	if err = b.Validate(); err != nil {
+		return wallet.Budget{}, err
+	}
+	if b.WalletID != nil {
+		if err = p.walletExists(ctx, fmt.Sprint(*b.WalletID)); err != nil {
+			return wallet.Budget{}, err
+		}
+	}
+	b, err = scanBudget(p.db().QueryRowContext(ctx, "INSERT INTO budgets (wallet_id, user_id, category, amount, period) VALUES ($1, $2, NULLIF($3, ''), $4, $5) RETURNING "+budgetColumns,
+		b.WalletID, b.UserID, strings.ToLower(strings.TrimSpace(b.Category)), b.Amount, b.Period))
+	return b, categoryError(err)
+}
+
+func (p *Postgres) DeleteBudget(ctx context.Context, id string) (_ wallet.Budget, err error) {
+	defer p.observe("DeleteBudget", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.Budget{}, wallet.ErrBudgetNotFound
+	}
+	return scanBudget(p.db().QueryRowContext(ctx, "DELETE FROM budgets WHERE id = $1 RETURNING "+budgetColumns, id))
+}
+
+// BudgetAlerts returns the thresholds a budget has reached, newest first.
+func (p *Postgres) BudgetAlerts(ctx context.Context, id string) (alerts []wallet.BudgetAlert, err error) {
+	defer p.observe("BudgetAlerts", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, wallet.ErrBudgetNotFound
+	}
+	var exists bool
+	if err = p.db().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM budgets WHERE id = $1)", id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, wallet.ErrBudgetNotFound
+	}
+
+	rows, err := p.db().QueryContext(ctx, "SELECT budget_id, period_start, threshold, spent, transaction_id, created_at FROM budget_alerts WHERE budget_id = $1 ORDER BY period_start DESC, threshold DESC", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts = []wallet.BudgetAlert{}
+	for rows.Next() {
+		var a wallet.BudgetAlert
+		if err = rows.Scan(&a.BudgetID, &a.PeriodStart, &a.Threshold, &a.Spent, &a.TransactionID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+// checkBudgets records an alert for every budget threshold that the
+// spending debit t, just inserted into the ledger of a wallet owned by
+// userID, crossed. Alerts are written in the caller's transaction, so they
+// only exist if the debit commits.
+func checkBudgets(ctx context.Context, q traced, userID int, t wallet.Transaction) error {
+	if t.Amount >= 0 || !slices.Contains(wallet.SpendingKinds, t.Kind) {
+		return nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT "+budgetColumns+" FROM budgets WHERE (wallet_id = $1 OR user_id = $2) AND (category IS NULL OR category = $3)", t.WalletID, userID, t.Category)
+	if err != nil {
+		return err
+	}
+	var budgets []wallet.Budget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		budgets = append(budgets, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, b := range budgets {
+		start := wallet.PeriodStart(b.Period, t.CreatedAt)
+		spent, err := budgetSpent(ctx, q, b, start)
+		if err != nil {
+			return err
+		}
+		for _, threshold := range wallet.CrossedThresholds(b.Amount, spent+t.Amount, spent) {
+			res, err := q.ExecContext(ctx, "INSERT INTO budget_alerts (budget_id, period_start, threshold, spent, transaction_id) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING",
+				b.ID, start, threshold, spent, t.ID)
+			if err != nil {
+				return err
+			}
+			if inserted, _ := res.RowsAffected(); inserted > 0 {
+				slog.InfoContext(ctx, "budget threshold reached", "budget_id", b.ID, "threshold", threshold, "spent", spent, "amount", b.Amount, "transaction_id", t.ID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const (
+	categoryColumns     = "name, COALESCE(description, ''), created_at"
+	categoryRuleColumns = "id, category, field, pattern, priority, created_at"
+)
+
+func scanCategory(row interface{ Scan(...any) error }) (wallet.Category, error) {
+	var c wallet.Category
+	err := row.Scan(&c.Name, &c.Description, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Category{}, wallet.ErrCategoryNotFound
+	}
+	return c, err
+}
+
+func scanCategoryRule(row interface{ Scan(...any) error }) (wallet.CategoryRule, error) {
+	var r wallet.CategoryRule
+	err := row.Scan(&r.ID, &r.Category, &r.Field, &r.Pattern, &r.Priority, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.CategoryRule{}, wallet.ErrRuleNotFound
+	}
+	return r, err
+}
+
+// categoryError maps constraint violations involving categories to wallet
+// errors: a duplicate name or a reference to a missing category.
+func categoryError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch {
+	case pqErr.Code == "23505" && pqErr.Table == "categories":
+		return wallet.ErrCategoryExists
+	case pqErr.Code == "23503" && slices.Contains(categoryForeignKeys, pqErr.Constraint):
+		return wallet.ErrUnknownCategory
+	}
+	return err
+}
+
+var categoryForeignKeys = []string{"wallet_transactions_category_fkey", "budgets_category_fkey", "category_rules_category_fkey"}
+
+func (p *Postgres) Categories(ctx context.Context) (categories []wallet.Category, err error) {
+	defer p.observe("Categories", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, "SELECT "+categoryColumns+" FROM categories ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories = []wallet.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+func (p *Postgres) CreateCategory(ctx context.Context, c wallet.Category) (_ wallet.Category, err error) {
+	defer p.observe("CreateCategory", time.Now(), &err)
+
+	if err = c.Validate(); err != nil {
+		return wallet.Category{}, err
+	}
+	c, err = scanCategory(p.db().QueryRowContext(ctx, "INSERT INTO categories (name, description) VALUES ($1, NULLIF($2, '')) RETURNING "+categoryColumns, c.Name, c.Description))
+	if err = categoryError(err); err != nil {
+		return wallet.Category{}, err
+	}
+
+	slog.InfoContext(ctx, "category created", "category", c.Name)
+	return c, nil
+}
+
+// UpdateCategory renames the category called name and replaces its
+// description. Entries, budgets and rules follow the rename.
+func (p *Postgres) UpdateCategory(ctx context.Context, name string, c wallet.Category) (_ wallet.Category, err error) {
+	defer p.observe("UpdateCategory", time.Now(), &err)
+
+	if err = c.Validate(); err != nil {
+		return wallet.Category{}, err
+	}
+	c, err = scanCategory(p.db().QueryRowContext(ctx, "UPDATE categories SET name = $2, description = NULLIF($3, '') WHERE name = $1 RETURNING "+categoryColumns, name, c.Name, c.Description))
+	if err = categoryError(err); err != nil {
+		return wallet.Category{}, err
+	}
+
+	slog.InfoContext(ctx, "category updated", "category", name, "new_name", c.Name)
+	return c, nil
+}
+
+// DeleteCategory deletes a category and its rules. Categories that entries
+// or budgets still use cannot be deleted.
+func (p *Postgres) DeleteCategory(ctx context.Context, name string) (_ wallet.Category, err error) {
+	defer p.observe("DeleteCategory", time.Now(), &err)
+
+	c, err := scanCategory(p.db().QueryRowContext(ctx, "DELETE FROM categories WHERE name = $1 RETURNING "+categoryColumns, name))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return wallet.Category{}, wallet.ErrCategoryInUse
+	}
+	if err != nil {
+		return wallet.Category{}, err
+	}
+
+	slog.InfoContext(ctx, "category deleted", "category", name)
+	return c, nil
+}
+
+// categoryRules returns the category rules in the order they are tried.
+func categoryRules(ctx context.Context, q querier) ([]wallet.CategoryRule, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+categoryRuleColumns+" FROM category_rules ORDER BY priority, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []wallet.CategoryRule{}
+	for rows.Next() {
+		r, err := scanCategoryRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func (p *Postgres) CategoryRules(ctx context.Context) (_ []wallet.CategoryRule, err error) {
+	defer p.observe("CategoryRules", time.Now(), &err)
+
+	return categoryRules(ctx, p.db())
+}
+
+func (p *Postgres) CreateCategoryRule(ctx context.Context, r wallet.CategoryRule) (_ wallet.CategoryRule, err error) {
+	defer p.observe("CreateCategoryRule", time.Now(), &err)
+
+	if err = r.Validate(); err != nil {
+		return wallet.CategoryRule{}, err
+	}
+	r, err = scanCategoryRule(p.db().QueryRowContext(ctx, "INSERT INTO category_rules (category, field, pattern, priority) VALUES ($1, $2, $3, $4) RETURNING "+categoryRuleColumns,
+		r.Category, r.Field, r.Pattern, r.Priority))
+	if err = categoryError(err); err != nil {
+		return wallet.CategoryRule{}, err
+	}
+
+	slog.InfoContext(ctx, "category rule created", "rule_id", r.ID, "category", r.Category)
+	return r, nil
+}
+
+func (p *Postgres) DeleteCategoryRule(ctx context.Context, id string) (_ wallet.CategoryRule, err error) {
+	defer p.observe("DeleteCategoryRule", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.CategoryRule{}, wallet.ErrRuleNotFound
+	}
+	return scanCategoryRule(p.db().QueryRowContext(ctx, "DELETE FROM category_rules WHERE id = $1 RETURNING "+categoryRuleColumns, id))
+}
+
+// spending totals the spending debits of the wallets matched by where, from
+// the start of from to the end of to, by category.
+func (p *Postgres) spending(ctx context.Context, from, to time.Time, where string, arg any) (wallet.SpendingReport, error) {
+	rows, err := p.db().QueryContext(ctx, `SELECT COALESCE(t.category, $1), -SUM(t.amount), COUNT(*)
+		FROM wallet_transactions t JOIN user_wallet w ON w.id = t.wallet_id
+		WHERE `+where+` AND t.amount < 0 AND t.kind = ANY($3) AND t.created_at >= $4 AND t.created_at < $5
+		GROUP BY 1 ORDER BY 2 DESC, 1`,
+		wallet.Uncategorized, arg, pq.Array(wallet.SpendingKinds), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return wallet.SpendingReport{}, err
+	}
+	defer rows.Close()
+
+	var categories []wallet.CategorySpending
+	for rows.Next() {
+		var c wallet.CategorySpending
+		if err := rows.Scan(&c.Category, &c.Amount, &c.Count); err != nil {
+			return wallet.SpendingReport{}, err
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return wallet.SpendingReport{}, err
+	}
+	return wallet.NewSpendingReport(from, to, categories), nil
+}
+
+// WalletSpending reports a wallet's spending by category between the dates
+// from and to, inclusive.
+func (p *Postgres) WalletSpending(ctx context.Context, walletID string, from, to time.Time) (_ wallet.SpendingReport, err error) {
+	defer p.observe("WalletSpending", time.Now(), &err)
+
+	if err = p.walletExists(ctx, walletID); err != nil {
+		return wallet.SpendingReport{}, err
+	}
+	return p.spending(ctx, from, to, "w.id = $2", walletID)
+}
+
+// UserSpending reports the spending of all of a user's wallets by category
+// between the dates from and to, inclusive.
+func (p *Postgres) UserSpending(ctx context.Context, userID string, from, to time.Time) (_ wallet.SpendingReport, err error) {
+	defer p.observe("UserSpending", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(userID, 10, 32); err != nil {
+		return wallet.SpendingReport{}, wallet.ErrNotFound
+	}
+	return p.spending(ctx, from, to, "w.user_id = $2", userID)
+}
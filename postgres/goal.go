@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const goalColumns = "id, wallet_id, name, target_amount, target_date, round_up_wallet_id, round_up_to, created_at"
+
+func scanGoal(row interface{ Scan(...any) error }) (wallet.Goal, error) {
+	var g wallet.Goal
+	var roundUpWalletID sql.NullInt64
+	err := row.Scan(&g.ID, &g.WalletID, &g.Name, &g.TargetAmount, &g.TargetDate, &roundUpWalletID, &g.RoundUpTo, &g.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Goal{}, wallet.ErrGoalNotFound
+	}
+	if roundUpWalletID.Valid {
+		id := int(roundUpWalletID.Int64)
+		g.RoundUpWalletID = &id
+	}
+	return g, err
+}
+
+// goalWallet checks that a wallet exists and that its type allows savings
+// goals, and returns its owner.
+func goalWallet(ctx context.Context, q querier, walletID any) (userID int, err error) {
+	var allows bool
+	err = q.QueryRowContext(ctx, "SELECT w.user_id, t.allows_goals FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type WHERE w.id = $1", walletID).Scan(&userID, &allows)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, wallet.ErrNotFound
+	}
+	if err == nil && !allows {
+		return 0, wallet.ErrNoGoals
+	}
+	return userID, err
+}
+
+// goalProgress computes a goal's progress from its wallet's balance and the
+// net amount added to it over the projection window, or since the wallet
+// was opened if that is more recent.
+func (p *Postgres) goalProgress(ctx context.Context, g wallet.Goal, now time.Time) (wallet.GoalProgress, error) {
+	var balance float64
+	var opened time.Time
+	err := p.db().QueryRowContext(ctx, "SELECT balance, created_at FROM user_wallet WHERE id = $1", g.WalletID).Scan(&balance, &opened)
+	if err != nil {
+		return wallet.GoalProgress{}, err
+	}
+	start := now.UTC().Add(-wallet.GoalProjectionWindow)
+	if opened.After(start) {
+		start = opened
+	}
+	before, err := p.balanceBefore(ctx, g.WalletID, start)
+	if err != nil {
+		return wallet.GoalProgress{}, err
+	}
+	days := now.Sub(start).Hours() / 24
+	return wallet.NewGoalProgress(g, balance, balance-before, days, now), nil
+}
+
+func (p *Postgres) Goals(ctx context.Context, walletID string, now time.Time) (_ []wallet.GoalProgress, err error) {
+	defer p.observe("Goals", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return nil, wallet.ErrNotFound
+	}
+	if _, err = goalWallet(ctx, p.db(), walletID); err != nil {
+		return nil, err
+	}
+	rows, err := p.db().QueryContext(ctx, "SELECT "+goalColumns+" FROM savings_goals WHERE wallet_id = $1 ORDER BY target_date, id", walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []wallet.Goal
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	progress := []wallet.GoalProgress{}
+	for _, g := range goals {
+		gp, err := p.goalProgress(ctx, g, now)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, gp)
+	}
+	return progress, nil
+}
+
+func (p *Postgres) Goal(ctx context.Context, walletID, goalID string, now time.Time) (_ wallet.GoalProgress, err error) {
+	defer p.observe("Goal", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return wallet.GoalProgress{}, wallet.ErrGoalNotFound
+	}
+	if _, err := strconv.ParseInt(goalID, 10, 64); err != nil {
+		return wallet.GoalProgress{}, wallet.ErrGoalNotFound
+	}
+	g, err := scanGoal(p.db().QueryRowContext(ctx, "SELECT "+goalColumns+" FROM savings_goals WHERE id = $1 AND wallet_id = $2", goalID, walletID))
+	if err != nil {
+		return wallet.GoalProgress{}, err
+	}
+	return p.goalProgress(ctx, g, now)
+}
+
+// CreateGoal adds a savings goal to a wallet. A round-up wallet must be
+// another wallet of the same user.
+func (p *Postgres) CreateGoal(ctx context.Context, walletID string, g wallet.Goal) (_ wallet.Goal, err error) {
+	defer p.observe("CreateGoal", time.Now(), &err)
+
+	if g.WalletID, err = strconv.Atoi(walletID); err != nil {
+		return wallet.Goal{}, wallet.ErrNotFound
+	}
+	if err = g.Validate(); err != nil {
+		return wallet.Goal{}, err
+	}
+	userID, err := goalWallet(ctx, p.db(), g.WalletID)
+	if err != nil {
+		return wallet.Goal{}, err
+	}
+	if g.RoundUpWalletID != nil {
+		var owner int
+		err = p.db().QueryRowContext(ctx, "SELECT user_id FROM user_wallet WHERE id = $1", *g.RoundUpWalletID).Scan(&owner)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && owner != userID) {
+			return wallet.Goal{}, wallet.ErrInvalidRoundUp
+		}
+		if err != nil {
+			return wallet.Goal{}, err
+		}
+	}
+
+	g, err = scanGoal(p.db().QueryRowContext(ctx, "INSERT INTO savings_goals (wallet_id, name, target_amount, target_date, round_up_wallet_id, round_up_to) VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+goalColumns,
+		g.WalletID, g.Name, g.TargetAmount, g.TargetDate.UTC(), g.RoundUpWalletID, g.RoundUpTo))
+	if err != nil {
+		return wallet.Goal{}, err
+	}
+
+	slog.DebugContext(ctx, "savings goal created", "wallet_id", g.WalletID, "goal_id", g.ID)
+	return g, nil
+}
+
+func (p *Postgres) DeleteGoal(ctx context.Context, walletID, goalID string) (_ wallet.Goal, err error) {
+	defer p.observe("DeleteGoal", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return wallet.Goal{}, wallet.ErrGoalNotFound
+	}
+	if _, err := strconv.ParseInt(goalID, 10, 64); err != nil {
+		return wallet.Goal{}, wallet.ErrGoalNotFound
+	}
+	return scanGoal(p.db().QueryRowContext(ctx, "DELETE FROM savings_goals WHERE id = $1 AND wallet_id = $2 RETURNING "+goalColumns, goalID, walletID))
+}
+
+// roundUp moves the round-up of the spending debit t into one unfinished
+// goal in the same currency that collects round-ups from t's wallet, chosen
+// by wallet.RoundUpGoal. The contribution runs under a savepoint: if the
+// source wallet cannot afford it, or the goal's wallet cannot be locked, it
+// is skipped and the debit itself still goes through.
+func (p *Postgres) roundUp(ctx context.Context, q traced, t wallet.Transaction) error {
+	if t.Amount >= 0 || !slices.Contains(wallet.SpendingKinds, t.Kind) {
+		return nil
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT g.id, g.wallet_id, g.round_up_to FROM savings_goals g
+		JOIN user_wallet w ON w.id = g.wallet_id JOIN user_wallet src ON src.id = g.round_up_wallet_id
+		WHERE g.round_up_wallet_id = $1 AND w.balance < g.target_amount AND w.currency = src.currency ORDER BY g.id`, t.WalletID)
+	if err != nil {
+		return err
+	}
+	var goals []wallet.Goal
+	for rows.Next() {
+		var g wallet.Goal
+		if err := rows.Scan(&g.ID, &g.WalletID, &g.RoundUpTo); err != nil {
+			rows.Close()
+			return err
+		}
+		goals = append(goals, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	g, amount := wallet.RoundUpGoal(t.Amount, goals)
+	if amount == 0 {
+		return nil
+	}
+	if _, err := q.ExecContext(ctx, "SAVEPOINT round_up"); err != nil {
+		return err
+	}
+	_, err = p.applyChange(ctx, q, fmt.Sprint(t.WalletID), wallet.Transaction{Kind: wallet.KindRoundUp, Amount: -amount})
+	if err == nil {
+		_, err = p.applyChange(ctx, q, fmt.Sprint(g.WalletID), wallet.Transaction{Kind: wallet.KindRoundUp, Amount: amount})
+	}
+	if err != nil {
+		if _, rollbackErr := q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT round_up"); rollbackErr != nil {
+			return rollbackErr
+		}
+		slog.DebugContext(ctx, "round-up skipped", "wallet_id", t.WalletID, "goal_id", g.ID, "amount", amount, "error", err)
+		return nil
+	}
+	if _, err := q.ExecContext(ctx, "RELEASE SAVEPOINT round_up"); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "round-up contributed", "wallet_id", t.WalletID, "goal_id", g.ID, "amount", amount)
+	return nil
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// heldSubquery totals the active, unexpired holds of the wallet aliased w.
+// Expired holds stop counting right away, before the expiry job marks them.
+// expires_at is a timestamptz, so the comparison does not depend on the
+// session's time zone.
+const heldSubquery = `COALESCE((SELECT SUM(h.amount) FROM holds h WHERE h.wallet_id = w.id AND h.status = 'active' AND h.expires_at > CURRENT_TIMESTAMP), 0)`
+
+const holdColumns = "id, wallet_id, amount, status, captured_amount, transaction_id, expires_at, created_at, updated_at"
+
+// heldAmount returns the funds reserved on a wallet by active holds.
+func heldAmount(ctx context.Context, q querier, walletID string) (float64, error) {
+	var held float64
+	err := q.QueryRowContext(ctx, "SELECT "+heldSubquery+" FROM user_wallet w WHERE w.id = $1", walletID).Scan(&held)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, wallet.ErrNotFound
+	}
+	return held, err
+}
+
+func scanHold(row interface{ Scan(...any) error }) (wallet.Hold, error) {
+	var h wallet.Hold
+	var txID sql.NullInt64
+	err := row.Scan(&h.ID, &h.WalletID, &h.Amount, &h.Status, &h.CapturedAmount, &txID, &h.ExpiresAt, &h.CreatedAt, &h.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Hold{}, wallet.ErrHoldNotFound
+	}
+	if txID.Valid {
+		h.TransactionID = &txID.Int64
+	}
+	return h, err
+}
+
+func (p *Postgres) Holds(ctx context.Context, walletID string) (holds []wallet.Hold, err error) {
+	defer p.observe("Holds", time.Now(), &err)
+
+	if err = p.walletExists(ctx, walletID); err != nil {
+		return nil, err
+	}
+	rows, err := p.db().QueryContext(ctx, "SELECT "+holdColumns+" FROM holds WHERE wallet_id = $1 ORDER BY id DESC", walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holds = []wallet.Hold{}
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+	return holds, rows.Err()
+}
+
+// PlaceHold reserves amount on a wallet until expiresAt. The hold must fit
+// the wallet's available balance under its type's rules, as a debit would.
+func (p *Postgres) PlaceHold(ctx context.Context, walletID string, amount float64, expiresAt time.Time) (_ wallet.Hold, err error) {
+	defer p.observe("PlaceHold", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	if err = p.checkChange(ctx, q, walletID, wallet.KindHold, -amount); err != nil {
+		return wallet.Hold{}, err
+	}
+	h, err := scanHold(q.QueryRowContext(ctx, "INSERT INTO holds (wallet_id, amount, expires_at) VALUES ($1, $2, $3) RETURNING "+holdColumns, walletID, amount, expiresAt.UTC()))
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Hold{}, err
+	}
+
+	slog.DebugContext(ctx, "hold placed", "wallet_id", walletID, "hold_id", h.ID, "amount", amount)
+	return h, nil
+}
+
+// CaptureHold settles amount of an active hold as a ledger debit and
+// releases the rest. A zero amount captures the whole hold.
+func (p *Postgres) CaptureHold(ctx context.Context, walletID, holdID string, amount float64) (_ wallet.Hold, err error) {
+	defer p.observe("CaptureHold", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	h, err := lockActiveHold(ctx, q, walletID, holdID)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	if amount == 0 {
+		amount = h.Amount
+	}
+	if amount > h.Amount {
+		return wallet.Hold{}, wallet.ErrCaptureExceedsHold
+	}
+
+	// The hold stops reserving funds first, so that the debit is checked
+	// against the balance it reserved.
+	_, err = q.ExecContext(ctx, "UPDATE holds SET status = $2, captured_amount = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1", h.ID, wallet.HoldCaptured, amount)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	t, err := p.applyChange(ctx, q, walletID, wallet.Transaction{Kind: wallet.KindCapture, Amount: -amount})
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	h, err = scanHold(q.QueryRowContext(ctx, "UPDATE holds SET transaction_id = $2 WHERE id = $1 RETURNING "+holdColumns, h.ID, t.ID))
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Hold{}, err
+	}
+
+	slog.DebugContext(ctx, "hold captured", "wallet_id", walletID, "hold_id", h.ID, "amount", amount, "balance", t.BalanceAfter)
+	return h, nil
+}
+
+// VoidHold releases an active hold without moving money.
+func (p *Postgres) VoidHold(ctx context.Context, walletID, holdID string) (_ wallet.Hold, err error) {
+	defer p.observe("VoidHold", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	h, err := lockActiveHold(ctx, q, walletID, holdID)
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	h, err = scanHold(q.QueryRowContext(ctx, "UPDATE holds SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING "+holdColumns, h.ID, wallet.HoldVoided))
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Hold{}, err
+	}
+
+	slog.DebugContext(ctx, "hold voided", "wallet_id", walletID, "hold_id", h.ID)
+	return h, nil
+}
+
+// lockActiveHold locks a hold of a wallet for capture or release. Ids that
+// are not numbers cannot match a hold.
+func lockActiveHold(ctx context.Context, q traced, walletID, holdID string) (wallet.Hold, error) {
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return wallet.Hold{}, wallet.ErrHoldNotFound
+	}
+	if _, err := strconv.ParseInt(holdID, 10, 64); err != nil {
+		return wallet.Hold{}, wallet.ErrHoldNotFound
+	}
+	h, err := scanHold(q.QueryRowContext(ctx, "SELECT "+holdColumns+" FROM holds WHERE id = $1 AND wallet_id = $2 FOR UPDATE", holdID, walletID))
+	if err != nil {
+		return wallet.Hold{}, err
+	}
+	if h.Status != wallet.HoldActive || !h.ExpiresAt.After(time.Now()) {
+		return wallet.Hold{}, wallet.ErrHoldNotActive
+	}
+	return h, nil
+}
+
+// ExpireHolds marks active holds past their expiry as expired, releasing
+// their funds.
+func (p *Postgres) ExpireHolds(ctx context.Context, now time.Time) (n int, err error) {
+	defer p.observe("ExpireHolds", time.Now(), &err)
+
+	res, err := p.db().ExecContext(ctx, "UPDATE holds SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE status = $2 AND expires_at <= $3", wallet.HoldExpired, wallet.HoldActive, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	expired, _ := res.RowsAffected()
+	if expired > 0 {
+		slog.InfoContext(ctx, "holds expired", "count", expired)
+	}
+	return int(expired), nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// holdingWallet checks that a wallet exists and that its type holds
+// assets.
+func holdingWallet(ctx context.Context, q querier, walletID string) error {
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return wallet.ErrNotFound
+	}
+	var holds bool
+	err := q.QueryRowContext(ctx, "SELECT t.holds_assets FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type WHERE w.id = $1", walletID).Scan(&holds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.ErrNotFound
+	}
+	if err == nil && !holds {
+		return wallet.ErrNoHoldings
+	}
+	return err
+}
+
+func (p *Postgres) Holdings(ctx context.Context, walletID string) (holdings []wallet.Holding, err error) {
+	defer p.observe("Holdings", time.Now(), &err)
+
+	if err = holdingWallet(ctx, p.db(), walletID); err != nil {
+		return nil, err
+	}
+	rows, err := p.db().QueryContext(ctx, "SELECT wallet_id, asset, quantity::text, updated_at FROM wallet_holdings WHERE wallet_id = $1 ORDER BY asset", walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holdings = []wallet.Holding{}
+	for rows.Next() {
+		var h wallet.Holding
+		if err = rows.Scan(&h.WalletID, &h.Asset, &h.Quantity, &h.UpdatedAt); err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, h)
+	}
+	return holdings, rows.Err()
+}
+
+// AddHolding adds quantity of asset to a wallet, creating the holding if
+// the wallet has none of the asset yet. Quantity must already be validated
+// with wallet.ParseQuantity.
+func (p *Postgres) AddHolding(ctx context.Context, walletID, asset, quantity string) (_ wallet.Holding, err error) {
+	defer p.observe("AddHolding", time.Now(), &err)
+
+	if err = holdingWallet(ctx, p.db(), walletID); err != nil {
+		return wallet.Holding{}, err
+	}
+	var h wallet.Holding
+	err = p.db().QueryRowContext(ctx, `INSERT INTO wallet_holdings (wallet_id, asset, quantity) VALUES ($1, $2, $3::numeric)
+		ON CONFLICT (wallet_id, asset) DO UPDATE SET quantity = wallet_holdings.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+		RETURNING wallet_id, asset, quantity::text, updated_at`, walletID, asset, quantity).Scan(&h.WalletID, &h.Asset, &h.Quantity, &h.UpdatedAt)
+	if err != nil {
+		return wallet.Holding{}, err
+	}
+
+	slog.DebugContext(ctx, "holding added", "wallet_id", walletID, "asset", asset, "amount", quantity)
+	return h, nil
+}
+
+// RemoveHolding takes quantity of asset out of a wallet, or all of it when
+// quantity is empty. A holding that reaches zero is deleted and returned
+// with a zero quantity.
+func (p *Postgres) RemoveHolding(ctx context.Context, walletID, asset, quantity string) (_ wallet.Holding, err error) {
+	defer p.observe("RemoveHolding", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Holding{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	if err = holdingWallet(ctx, q, walletID); err != nil {
+		return wallet.Holding{}, err
+	}
+	h := wallet.Holding{}
+	err = q.QueryRowContext(ctx, "SELECT wallet_id, asset, quantity::text FROM wallet_holdings WHERE wallet_id = $1 AND asset = $2 FOR UPDATE", walletID, asset).Scan(&h.WalletID, &h.Asset, &h.Quantity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Holding{}, wallet.ErrHoldingNotFound
+	}
+	if err != nil {
+		return wallet.Holding{}, err
+	}
+
+	held, _ := new(big.Rat).SetString(h.Quantity)
+	remove := held
+	if quantity != "" {
+		remove, _ = new(big.Rat).SetString(quantity)
+	}
+	switch held.Cmp(remove) {
+	case -1:
+		return wallet.Holding{}, wallet.ErrInsufficientHolding
+	case 0:
+		_, err = q.ExecContext(ctx, "DELETE FROM wallet_holdings WHERE wallet_id = $1 AND asset = $2", walletID, asset)
+		h.Quantity, h.UpdatedAt = new(big.Rat).FloatString(wallet.QuantityDecimals), time.Now()
+	default:
+		err = q.QueryRowContext(ctx, "UPDATE wallet_holdings SET quantity = quantity - $3::numeric, updated_at = CURRENT_TIMESTAMP WHERE wallet_id = $1 AND asset = $2 RETURNING quantity::text, updated_at",
+			walletID, asset, remove.FloatString(wallet.QuantityDecimals)).Scan(&h.Quantity, &h.UpdatedAt)
+	}
+	if err != nil {
+		return wallet.Holding{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Holding{}, err
+	}
+
+	slog.DebugContext(ctx, "holding removed", "wallet_id", walletID, "asset", asset, "amount", remove.FloatString(wallet.QuantityDecimals))
+	return h, nil
+}
+
+// Valuation prices a wallet's holdings with the configured price table.
+// An empty currency means the configured default.
+func (p *Postgres) Valuation(ctx context.Context, walletID, currency string) (_ wallet.Valuation, err error) {
+	defer p.observe("Valuation", time.Now(), &err)
+
+	id, err := strconv.Atoi(walletID)
+	if err != nil {
+		return wallet.Valuation{}, wallet.ErrNotFound
+	}
+	holdings, err := p.Holdings(ctx, walletID)
+	if err != nil {
+		return wallet.Valuation{}, err
+	}
+	if currency == "" {
+		currency = p.Crypto.DefaultCurrency
+	}
+	currency = strings.ToUpper(currency)
+	return wallet.Value(id, holdings, currency, p.Crypto.Prices[currency])
+}
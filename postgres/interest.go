@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// AccrueInterest records a day of interest for every completed day since a
+// wallet's last accrual, or since it was opened, up to the day before now,
+// but never for days before its type started earning interest.
+// Each day is accrued on that day's closing balance from the ledger. Days
+// already accrued are skipped, so the job can safely run again after a
+// restart.
+func (p *Postgres) AccrueInterest(ctx context.Context, now time.Time) (n int, err error) {
+	defer p.observe("AccrueInterest", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, `SELECT w.id, d::date,
+			COALESCE((SELECT x.balance_after FROM wallet_transactions x
+				WHERE x.wallet_id = w.id AND x.created_at < d + interval '1 day'
+				ORDER BY x.created_at DESC, x.id DESC LIMIT 1), 0)
+		FROM user_wallet w
+		JOIN wallet_types t ON t.name = w.wallet_type
+		CROSS JOIN LATERAL generate_series(
+			GREATEST(COALESCE((SELECT MAX(a.accrual_date) + 1 FROM interest_accruals a WHERE a.wallet_id = w.id), w.created_at::date), t.interest_since::date),
+			$1::date - 1, interval '1 day') AS d
+		WHERE t.earns_interest`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	type day struct {
+		walletID int
+		date     time.Time
+		balance  float64
+	}
+	var days []day
+	for rows.Next() {
+		var d day
+		if err = rows.Scan(&d.walletID, &d.date, &d.balance); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		days = append(days, d)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tiers := p.interest().Tiers
+	for _, d := range days {
+		res, err := p.db().ExecContext(ctx, "INSERT INTO interest_accruals (wallet_id, accrual_date, balance, amount) VALUES ($1, $2, $3, $4) ON CONFLICT (wallet_id, accrual_date) DO NOTHING",
+			d.walletID, d.date, d.balance, wallet.DailyInterest(d.balance, tiers))
+		if err != nil {
+			return n, fmt.Errorf("wallet %d: %w", d.walletID, err)
+		}
+		inserted, _ := res.RowsAffected()
+		n += int(inserted)
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "interest accrued", "days", n)
+	}
+	return n, nil
+}
+
+// PostInterest pays out the unposted accruals of completed months as one
+// interest ledger entry per wallet. The total is rounded to the wallet
+// type's decimal places; the remainder is not carried over.
+func (p *Postgres) PostInterest(ctx context.Context, now time.Time) (n int, err error) {
+	defer p.observe("PostInterest", time.Now(), &err)
+
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	rows, err := p.db().QueryContext(ctx, "SELECT DISTINCT wallet_id FROM interest_accruals WHERE posted_at IS NULL AND accrual_date < $1", monthStart)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		posted, err := p.postInterest(ctx, id, monthStart)
+		if err != nil {
+			return n, fmt.Errorf("wallet %d: %w", id, err)
+		}
+		if posted {
+			n++
+		}
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "interest posted", "wallets", n)
+	}
+	return n, nil
+}
+
+func (p *Postgres) postInterest(ctx context.Context, walletID int, before time.Time) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	// Marking the accruals first locks them: a concurrent posting waits and
+	// then finds nothing left to post.
+	rows, err := q.QueryContext(ctx, "UPDATE interest_accruals SET posted_at = CURRENT_TIMESTAMP WHERE wallet_id = $1 AND posted_at IS NULL AND accrual_date < $2 RETURNING accrual_date, amount", walletID, before)
+	if err != nil {
+		return false, err
+	}
+	var dates []string
+	var total float64
+	for rows.Next() {
+		var date time.Time
+		var amount float64
+		if err = rows.Scan(&date, &amount); err != nil {
+			rows.Close()
+			return false, err
+		}
+		dates = append(dates, date.Format(time.DateOnly))
+		total += amount
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	var decimals int
+	err = q.QueryRowContext(ctx, "SELECT t.decimals FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type WHERE w.id = $1", walletID).Scan(&decimals)
+	if err != nil {
+		return false, err
+	}
+	amount := wallet.Round(total, decimals)
+	if amount > 0 {
+		t, err := p.applyChange(ctx, q, fmt.Sprint(walletID), wallet.Transaction{Kind: wallet.KindInterest, Amount: amount})
+		if err != nil {
+			return false, err
+		}
+		_, err = q.ExecContext(ctx, "UPDATE interest_accruals SET posted_transaction_id = $3 WHERE wallet_id = $1 AND accrual_date = ANY($2::date[])", walletID, pq.Array(dates), t.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	slog.DebugContext(ctx, "interest posted", "wallet_id", walletID, "days", len(dates), "amount", amount)
+	return amount > 0, nil
+}
+
+// InterestPreview reports what posting the wallet's accrued interest would
+// pay if it ran now, without writing anything.
+func (p *Postgres) InterestPreview(ctx context.Context, walletID string, now time.Time) (_ wallet.InterestPreview, err error) {
+	defer p.observe("InterestPreview", time.Now(), &err)
+
+	var preview wallet.InterestPreview
+	var earns bool
+	var decimals int
+	err = p.db().QueryRowContext(ctx, "SELECT w.id, w.balance, t.earns_interest, t.decimals FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type WHERE w.id = $1", walletID).
+		Scan(&preview.WalletID, &preview.Balance, &earns, &decimals)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.InterestPreview{}, wallet.ErrNotFound
+	}
+	if err != nil {
+		return wallet.InterestPreview{}, err
+	}
+	if !earns {
+		return wallet.InterestPreview{}, wallet.ErrNoInterest
+	}
+
+	var from, to sql.NullTime
+	err = p.db().QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(amount), 0), MIN(accrual_date), MAX(accrual_date) FROM interest_accruals WHERE wallet_id = $1 AND posted_at IS NULL", walletID).
+		Scan(&preview.AccruedDays, &preview.Accrued, &from, &to)
+	if err != nil {
+		return wallet.InterestPreview{}, err
+	}
+	preview.Accrued = wallet.Round(preview.Accrued, decimals)
+	if from.Valid && to.Valid {
+		preview.AccruedFrom, preview.AccruedTo = &from.Time, &to.Time
+	}
+
+	tiers := p.interest().Tiers
+	preview.DailyInterest = wallet.DailyInterest(preview.Balance, tiers)
+	if preview.Balance > 0 {
+		preview.AnnualRate = wallet.AnnualInterest(preview.Balance, tiers) / preview.Balance
+	}
+	now = now.UTC()
+	preview.NextPosting = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return preview, nil
+}
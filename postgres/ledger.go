@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// ledgerCheckLimit caps each list in a ledger check.
+const ledgerCheckLimit = 100
+
+// walletAccount returns the id of a wallet's ledger account and its
+// currency, opening the account on first use.
+func walletAccount(ctx context.Context, q traced, walletID int) (int64, string, error) {
+	var id int64
+	var currency string
+	err := q.QueryRowContext(ctx, "SELECT id, currency FROM accounts WHERE wallet_id = $1", walletID).Scan(&id, &currency)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return id, currency, err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO accounts (code, kind, wallet_id, currency)
+		SELECT 'wallet:' || id, $2, id, currency FROM user_wallet WHERE id = $1
+		ON CONFLICT DO NOTHING`, walletID, wallet.AccountWallet)
+	if err != nil {
+		return 0, "", err
+	}
+	err = q.QueryRowContext(ctx, "SELECT id, currency FROM accounts WHERE wallet_id = $1", walletID).Scan(&id, &currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", wallet.ErrNotFound
+	}
+	return id, currency, err
+}
+
+// systemAccount returns the id of a system account, opening it on first
+// use. It never updates the row, so that concurrent writers posting to the
+// same system account do not queue on its lock.
+func systemAccount(ctx context.Context, q traced, name, currency string) (int64, error) {
+	code := wallet.SystemAccountCode(name, currency)
+	var id int64
+	err := q.QueryRowContext(ctx, "SELECT id FROM accounts WHERE code = $1", code).Scan(&id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return id, err
+	}
+	_, err = q.ExecContext(ctx, "INSERT INTO accounts (code, kind, currency) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", code, wallet.AccountSystem, currency)
+	if err != nil {
+		return 0, err
+	}
+	err = q.QueryRowContext(ctx, "SELECT id FROM accounts WHERE code = $1", code).Scan(&id)
+	return id, err
+}
+
+// postJournal records t, which has just been inserted, as a journal entry
+// debiting or crediting the wallet's account against the system account on
+// the other side, within the caller's transaction.
+func postJournal(ctx context.Context, q traced, t wallet.Transaction) error {
+	account, currency, err := walletAccount(ctx, q, t.WalletID)
+	if err != nil {
+		return err
+	}
+	counter, err := systemAccount(ctx, q, wallet.CounterAccount(t.Kind), currency)
+	if err != nil {
+		return err
+	}
+	var entryID int64
+	err = q.QueryRowContext(ctx, "INSERT INTO journal_entries (transaction_id, kind, created_at) VALUES ($1, $2, $3) RETURNING id", t.ID, t.Kind, t.CreatedAt).Scan(&entryID)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, "INSERT INTO postings (entry_id, account_id, amount) VALUES ($1, $2, $3), ($1, $4, -$3::numeric)", entryID, account, t.Amount, counter)
+	return err
+}
+
+// LedgerAccounts lists the system accounts with their balances.
+func (p *Postgres) LedgerAccounts(ctx context.Context) (_ []wallet.Account, err error) {
+	defer p.observe("LedgerAccounts", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, `SELECT a.id, a.code, a.kind, a.currency, COALESCE(SUM(p.amount), 0)
+		FROM accounts a LEFT JOIN postings p ON p.account_id = a.id
+		WHERE a.kind = $1
+		GROUP BY a.id ORDER BY a.currency, a.code`, wallet.AccountSystem)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []wallet.Account{}
+	for rows.Next() {
+		var a wallet.Account
+		if err := rows.Scan(&a.ID, &a.Code, &a.Kind, &a.Currency, &a.Balance); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+func (p *Postgres) JournalEntry(ctx context.Context, id string) (_ wallet.JournalEntry, err error) {
+	defer p.observe("JournalEntry", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.JournalEntry{}, wallet.ErrEntryNotFound
+	}
+	var e wallet.JournalEntry
+	err = p.db().QueryRowContext(ctx, "SELECT id, transaction_id, kind, created_at FROM journal_entries WHERE id = $1", id).Scan(&e.ID, &e.TransactionID, &e.Kind, &e.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.JournalEntry{}, wallet.ErrEntryNotFound
+	}
+	if err != nil {
+		return wallet.JournalEntry{}, err
+	}
+
+	rows, err := p.db().QueryContext(ctx, `SELECT a.id, a.code, p.amount
+		FROM postings p JOIN accounts a ON a.id = p.account_id
+		WHERE p.entry_id = $1 ORDER BY p.id`, e.ID)
+	if err != nil {
+		return wallet.JournalEntry{}, err
+	}
+	defer rows.Close()
+
+	e.Postings = []wallet.Posting{}
+	for rows.Next() {
+		var posting wallet.Posting
+		if err := rows.Scan(&posting.AccountID, &posting.AccountCode, &posting.Amount); err != nil {
+			return wallet.JournalEntry{}, err
+		}
+		e.Postings = append(e.Postings, posting)
+	}
+	return e, rows.Err()
+}
+
+// CheckLedger checks the ledger's invariants against one snapshot of the
+// database. Sums are compared in NUMERIC, so a mismatch of any size is
+// reported.
+func (p *Postgres) CheckLedger(ctx context.Context, now time.Time) (_ wallet.LedgerCheck, err error) {
+	defer p.observe("CheckLedger", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	check := wallet.LedgerCheck{CheckedAt: now.UTC()}
+	check.Totals, err = currencyTotals(ctx, q, `SELECT a.currency, SUM(p.amount)
+		FROM postings p JOIN accounts a ON a.id = p.account_id
+		GROUP BY a.currency ORDER BY a.currency`)
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+	check.Clearing, err = currencyTotals(ctx, q, `SELECT a.currency, SUM(p.amount)
+		FROM postings p JOIN accounts a ON a.id = p.account_id
+		WHERE a.kind = $1 AND a.code = $2 || ':' || a.currency
+		GROUP BY a.currency HAVING SUM(p.amount) <> 0 ORDER BY a.currency`, wallet.AccountSystem, wallet.SystemClearing)
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+
+	check.UnbalancedCount, check.UnbalancedEntries, err = unbalancedEntries(ctx, q)
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+	check.MismatchCount, check.BalanceMismatches, err = balanceMismatches(ctx, q)
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+	err = q.QueryRowContext(ctx, `SELECT COUNT(*) FROM wallet_transactions t
+		WHERE NOT EXISTS (SELECT 1 FROM journal_entries j WHERE j.transaction_id = t.id)`).Scan(&check.UnpostedCount)
+	if err != nil {
+		return wallet.LedgerCheck{}, err
+	}
+
+	check.OK = check.Passed()
+	return check, nil
+}
+
+func currencyTotals(ctx context.Context, q traced, query string, args ...any) ([]wallet.CurrencyTotal, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []wallet.CurrencyTotal{}
+	for rows.Next() {
+		var t wallet.CurrencyTotal
+		if err := rows.Scan(&t.Currency, &t.Total); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func unbalancedEntries(ctx context.Context, q traced) (int, []wallet.UnbalancedEntry, error) {
+	rows, err := q.QueryContext(ctx, `SELECT entry_id, total, COUNT(*) OVER ()
+		FROM (SELECT entry_id, SUM(amount) AS total FROM postings GROUP BY entry_id HAVING SUM(amount) <> 0) u
+		ORDER BY entry_id LIMIT $1`, ledgerCheckLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var count int
+	entries := []wallet.UnbalancedEntry{}
+	for rows.Next() {
+		var e wallet.UnbalancedEntry
+		if err := rows.Scan(&e.EntryID, &e.Total, &count); err != nil {
+			return 0, nil, err
+		}
+		entries = append(entries, e)
+	}
+	return count, entries, rows.Err()
+}
+
+func balanceMismatches(ctx context.Context, q traced) (int, []wallet.BalanceMismatch, error) {
+	rows, err := q.QueryContext(ctx, `SELECT id, balance, derived, COUNT(*) OVER ()
+		FROM (SELECT w.id, w.balance, COALESCE((SELECT SUM(p.amount) FROM accounts a JOIN postings p ON p.account_id = a.id WHERE a.wallet_id = w.id), 0) AS derived
+			FROM user_wallet w) m
+		WHERE balance <> derived
+		ORDER BY id LIMIT $1`, ledgerCheckLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var count int
+	mismatches := []wallet.BalanceMismatch{}
+	for rows.Next() {
+		var m wallet.BalanceMismatch
+		if err := rows.Scan(&m.WalletID, &m.Cached, &m.Derived, &count); err != nil {
+			return 0, nil, err
+		}
+		mismatches = append(mismatches, m)
+	}
+	return count, mismatches, rows.Err()
+}
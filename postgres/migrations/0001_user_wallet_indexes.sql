@@ -0,0 +1,3 @@
+-- Lookups by owner and by type are the two filters the API exposes.
+CREATE INDEX IF NOT EXISTS user_wallet_user_id_idx ON user_wallet (user_id);
+CREATE INDEX IF NOT EXISTS user_wallet_wallet_type_idx ON user_wallet (wallet_type);
@@ -0,0 +1,16 @@
+-- Ledger of every balance change. Amounts are signed: credits are positive,
+-- debits negative, so a wallet's balance is the sum of its entries.
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+	id BIGSERIAL PRIMARY KEY,
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	kind VARCHAR(32) NOT NULL,
+	amount DECIMAL(10, 2) NOT NULL,
+	balance_after DECIMAL(10, 2) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS wallet_transactions_wallet_id_created_at_idx ON wallet_transactions (wallet_id, created_at);
+
+-- Explain existing balances with an opening entry each.
+INSERT INTO wallet_transactions (wallet_id, kind, amount, balance_after, created_at)
+SELECT id, 'opening', balance, balance, created_at FROM user_wallet WHERE balance <> 0;
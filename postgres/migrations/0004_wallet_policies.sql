@@ -0,0 +1,10 @@
+-- Crypto wallets need more than two decimal places.
+ALTER TABLE user_wallet ALTER COLUMN balance TYPE NUMERIC(20, 8);
+ALTER TABLE wallet_transactions
+	ALTER COLUMN amount TYPE NUMERIC(20, 8),
+	ALTER COLUMN balance_after TYPE NUMERIC(20, 8);
+
+-- NULL means the wallet type's default limit applies.
+ALTER TABLE user_wallet ADD COLUMN IF NOT EXISTS credit_limit NUMERIC(20, 2);
+
+CREATE INDEX IF NOT EXISTS wallet_transactions_withdrawals_idx ON wallet_transactions (wallet_id, created_at) WHERE kind = 'withdrawal';
@@ -0,0 +1,23 @@
+-- Wallet types become data instead of a Postgres enum so that they can be
+-- managed through the API.
+CREATE TABLE IF NOT EXISTS wallet_types (
+	name VARCHAR(64) PRIMARY KEY,
+	allows_negative BOOLEAN NOT NULL DEFAULT FALSE,
+	decimals INT NOT NULL DEFAULT 2 CHECK (decimals BETWEEN 0 AND 8),
+	default_credit_limit NUMERIC(20, 2) NOT NULL DEFAULT 0 CHECK (default_credit_limit >= 0),
+	monthly_withdrawal_cap NUMERIC(20, 2) NOT NULL DEFAULT 0 CHECK (monthly_withdrawal_cap >= 0),
+	retired_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO wallet_types (name, allows_negative, decimals, default_credit_limit, monthly_withdrawal_cap) VALUES
+('Savings', FALSE, 2, 0, 50000),
+('Credit Card', TRUE, 2, 1000, 0),
+('Crypto Wallet', FALSE, 8, 0, 0)
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE user_wallet ALTER COLUMN wallet_type TYPE VARCHAR(64) USING wallet_type::text;
+ALTER TABLE user_wallet ADD CONSTRAINT user_wallet_wallet_type_fkey
+	FOREIGN KEY (wallet_type) REFERENCES wallet_types (name) ON UPDATE CASCADE;
+
+DROP TYPE IF EXISTS wallet_type;
@@ -0,0 +1,24 @@
+-- Monthly statements of wallets whose type allows negative balances, i.e.
+-- credit cards. Amounts owed are shown as negative balances, as in the
+-- ledger.
+CREATE TABLE IF NOT EXISTS statements (
+	id BIGSERIAL PRIMARY KEY,
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	period_start TIMESTAMP NOT NULL,
+	period_end TIMESTAMP NOT NULL,
+	opening_balance NUMERIC(20, 8) NOT NULL,
+	closing_balance NUMERIC(20, 8) NOT NULL,
+	total_credits NUMERIC(20, 8) NOT NULL,
+	total_debits NUMERIC(20, 8) NOT NULL,
+	minimum_payment NUMERIC(20, 8) NOT NULL,
+	due_date DATE NOT NULL,
+	-- late_fee_assessed_at is set once the due date has passed and the
+	-- payments were checked, whether or not a fee was posted.
+	late_fee NUMERIC(20, 8) NOT NULL DEFAULT 0,
+	late_fee_transaction_id BIGINT REFERENCES wallet_transactions (id) ON DELETE SET NULL,
+	late_fee_assessed_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (wallet_id, period_end)
+);
+
+CREATE INDEX IF NOT EXISTS statements_unassessed_due_date_idx ON statements (due_date) WHERE late_fee_assessed_at IS NULL;
@@ -0,0 +1,19 @@
+ALTER TABLE wallet_types ADD COLUMN IF NOT EXISTS earns_interest BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE wallet_types SET earns_interest = TRUE WHERE name = 'Savings';
+
+-- One row per wallet and day. The primary key makes accrual idempotent: a
+-- day that was already accrued is skipped when the job runs again.
+-- posted_at marks accruals included in a monthly posting; the posting's
+-- ledger entry is linked unless the month's interest rounded to zero.
+CREATE TABLE IF NOT EXISTS interest_accruals (
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	accrual_date DATE NOT NULL,
+	balance NUMERIC(20, 8) NOT NULL,
+	amount NUMERIC(20, 8) NOT NULL,
+	posted_at TIMESTAMP,
+	posted_transaction_id BIGINT REFERENCES wallet_transactions (id) ON DELETE SET NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (wallet_id, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS interest_accruals_unposted_idx ON interest_accruals (wallet_id, accrual_date) WHERE posted_at IS NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE wallet_types ADD COLUMN IF NOT EXISTS holds_assets BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE wallet_types SET holds_assets = TRUE WHERE name = 'Crypto Wallet';
+
+-- Asset quantities of wallets whose type holds assets. Quantities keep up
+-- to 18 decimal places, the precision of e.g. ETH.
+CREATE TABLE IF NOT EXISTS wallet_holdings (
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	asset VARCHAR(16) NOT NULL,
+	quantity NUMERIC(38, 18) NOT NULL CHECK (quantity > 0),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (wallet_id, asset)
+);
@@ -0,0 +1,17 @@
+-- Funds reserved on a wallet ahead of settlement. Active holds that have not
+-- expired reduce the wallet's available balance; a capture turns (part of) a
+-- hold into a ledger debit and releases the rest.
+CREATE TABLE IF NOT EXISTS holds (
+	id BIGSERIAL PRIMARY KEY,
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	amount NUMERIC(20, 8) NOT NULL CHECK (amount > 0),
+	status VARCHAR(16) NOT NULL DEFAULT 'active',
+	captured_amount NUMERIC(20, 8) NOT NULL DEFAULT 0,
+	transaction_id BIGINT REFERENCES wallet_transactions (id) ON DELETE SET NULL,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS holds_active_wallet_id_idx ON holds (wallet_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS holds_active_expires_at_idx ON holds (expires_at) WHERE status = 'active';
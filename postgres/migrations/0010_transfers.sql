@@ -0,0 +1,51 @@
+-- Standing orders. next_run_at is the schedule's next run; retry_at is set
+-- while a failed run waits to be tried again.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+	id BIGSERIAL PRIMARY KEY,
+	from_wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	to_wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	amount NUMERIC(20, 8) NOT NULL CHECK (amount > 0),
+	schedule TEXT NOT NULL,
+	starts_at TIMESTAMP NOT NULL,
+	ends_at TIMESTAMP,
+	status VARCHAR(16) NOT NULL DEFAULT 'active',
+	next_run_at TIMESTAMP,
+	retry_at TIMESTAMP,
+	attempts INT NOT NULL DEFAULT 0,
+	failure_count INT NOT NULL DEFAULT 0,
+	last_error TEXT,
+	last_run_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_transfers_due_idx ON scheduled_transfers ((COALESCE(retry_at, next_run_at))) WHERE status = 'active';
+
+-- A transfer is a debit and a credit entry in the ledger.
+CREATE TABLE IF NOT EXISTS transfers (
+	id BIGSERIAL PRIMARY KEY,
+	from_wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	to_wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	amount NUMERIC(20, 8) NOT NULL CHECK (amount > 0),
+	debit_transaction_id BIGINT NOT NULL REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	credit_transaction_id BIGINT NOT NULL REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	scheduled_transfer_id BIGINT REFERENCES scheduled_transfers (id) ON DELETE SET NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS transfers_from_wallet_id_idx ON transfers (from_wallet_id);
+CREATE INDEX IF NOT EXISTS transfers_to_wallet_id_idx ON transfers (to_wallet_id);
+
+-- Every attempt to execute a scheduled transfer, successful or not.
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+	id BIGSERIAL PRIMARY KEY,
+	scheduled_transfer_id BIGINT NOT NULL REFERENCES scheduled_transfers (id) ON DELETE CASCADE,
+	scheduled_for TIMESTAMP NOT NULL,
+	attempt INT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	error TEXT,
+	transfer_id BIGINT REFERENCES transfers (id) ON DELETE SET NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_transfer_runs_scheduled_transfer_id_idx ON scheduled_transfer_runs (scheduled_transfer_id);
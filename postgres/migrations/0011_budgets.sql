@@ -0,0 +1,30 @@
+-- Debits can be tagged with a spending category, which budgets filter on.
+ALTER TABLE wallet_transactions ADD COLUMN IF NOT EXISTS category VARCHAR(64);
+
+-- A budget caps spending per period on one wallet or across a user's
+-- wallets, optionally in a single category.
+CREATE TABLE IF NOT EXISTS budgets (
+	id BIGSERIAL PRIMARY KEY,
+	wallet_id INT REFERENCES user_wallet (id) ON DELETE CASCADE,
+	user_id INT,
+	category VARCHAR(64),
+	amount NUMERIC(20, 8) NOT NULL CHECK (amount > 0),
+	period VARCHAR(16) NOT NULL CHECK (period IN ('daily', 'weekly', 'monthly')),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK ((wallet_id IS NULL) <> (user_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS budgets_wallet_id_idx ON budgets (wallet_id);
+CREATE INDEX IF NOT EXISTS budgets_user_id_idx ON budgets (user_id);
+
+-- Threshold crossings, written in the same transaction as the debit that
+-- caused them. The primary key makes each alert fire once per period.
+CREATE TABLE IF NOT EXISTS budget_alerts (
+	budget_id BIGINT NOT NULL REFERENCES budgets (id) ON DELETE CASCADE,
+	period_start TIMESTAMP NOT NULL,
+	threshold INT NOT NULL,
+	spent NUMERIC(20, 8) NOT NULL,
+	transaction_id BIGINT NOT NULL REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (budget_id, period_start, threshold)
+);
@@ -0,0 +1,18 @@
+ALTER TABLE wallet_types ADD COLUMN IF NOT EXISTS allows_goals BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE wallet_types SET allows_goals = TRUE WHERE name = 'Savings';
+
+-- Savings targets on wallets whose type allows goals. A goal with a
+-- round-up wallet collects the round-up of each spending debit on it.
+CREATE TABLE IF NOT EXISTS savings_goals (
+	id BIGSERIAL PRIMARY KEY,
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	name VARCHAR(100) NOT NULL,
+	target_amount NUMERIC(20, 8) NOT NULL CHECK (target_amount > 0),
+	target_date DATE NOT NULL,
+	round_up_wallet_id INT REFERENCES user_wallet (id) ON DELETE SET NULL,
+	round_up_to NUMERIC(20, 8) NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS savings_goals_wallet_id_idx ON savings_goals (wallet_id);
+CREATE INDEX IF NOT EXISTS savings_goals_round_up_wallet_id_idx ON savings_goals (round_up_wallet_id) WHERE round_up_wallet_id IS NOT NULL;
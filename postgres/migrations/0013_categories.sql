@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS categories (
+	name VARCHAR(64) PRIMARY KEY,
+	description VARCHAR(200),
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO categories (name) VALUES
+	('groceries'), ('rent'), ('salary'), ('utilities'), ('transport'),
+	('dining'), ('shopping'), ('entertainment'), ('health'), ('travel')
+ON CONFLICT DO NOTHING;
+
+-- Categories typed in before they were managed become managed ones.
+INSERT INTO categories (name)
+SELECT category FROM wallet_transactions WHERE category IS NOT NULL
+UNION SELECT category FROM budgets WHERE category IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE wallet_transactions
+	ADD COLUMN IF NOT EXISTS merchant VARCHAR(100),
+	ADD COLUMN IF NOT EXISTS description VARCHAR(500),
+	ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+	ADD CONSTRAINT wallet_transactions_category_fkey FOREIGN KEY (category) REFERENCES categories (name) ON UPDATE CASCADE;
+
+ALTER TABLE budgets
+	ADD CONSTRAINT budgets_category_fkey FOREIGN KEY (category) REFERENCES categories (name) ON UPDATE CASCADE;
+
+CREATE INDEX IF NOT EXISTS wallet_transactions_category_idx ON wallet_transactions (category) WHERE category IS NOT NULL;
+
+-- Rules that categorise new entries by merchant or description. Patterns
+-- are case-insensitive regular expressions, evaluated by the application.
+CREATE TABLE IF NOT EXISTS category_rules (
+	id BIGSERIAL PRIMARY KEY,
+	category VARCHAR(64) NOT NULL REFERENCES categories (name) ON UPDATE CASCADE ON DELETE CASCADE,
+	field VARCHAR(16) NOT NULL CHECK (field IN ('merchant', 'description')),
+	pattern VARCHAR(200) NOT NULL,
+	priority INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
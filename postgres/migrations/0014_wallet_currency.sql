@@ -0,0 +1,3 @@
+-- Existing wallets predate currencies and are taken to be in the default
+-- currency.
+ALTER TABLE user_wallet ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
@@ -0,0 +1,10 @@
+-- Closing balance of each wallet at the end of each UTC day, so that
+-- balances in the past only need the ledger entries since the nearest
+-- snapshot. Snapshots are derived from the ledger and can be rebuilt.
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	snapshot_date DATE NOT NULL,
+	balance NUMERIC(20, 8) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (wallet_id, snapshot_date)
+);
@@ -0,0 +1,67 @@
+-- Double-entry ledger. Every wallet_transactions row is explained by a
+-- journal entry whose postings sum to zero: one on the wallet's account and
+-- one on the system account on the other side of the movement. Postings
+-- are signed so that a wallet account's postings sum to its balance, which
+-- user_wallet.balance caches.
+CREATE TABLE IF NOT EXISTS accounts (
+	id BIGSERIAL PRIMARY KEY,
+	code VARCHAR(64) NOT NULL UNIQUE,
+	kind VARCHAR(16) NOT NULL CHECK (kind IN ('wallet', 'system')),
+	wallet_id INT UNIQUE REFERENCES user_wallet (id) ON DELETE CASCADE,
+	currency CHAR(3) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK ((kind = 'wallet') = (wallet_id IS NOT NULL))
+);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+	id BIGSERIAL PRIMARY KEY,
+	transaction_id BIGINT NOT NULL UNIQUE REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	kind VARCHAR(32) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS postings (
+	id BIGSERIAL PRIMARY KEY,
+	entry_id BIGINT NOT NULL REFERENCES journal_entries (id) ON DELETE CASCADE,
+	account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+	amount NUMERIC(20, 8) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS postings_entry_id_idx ON postings (entry_id);
+CREATE INDEX IF NOT EXISTS postings_account_id_idx ON postings (account_id);
+
+-- Post the existing ledger.
+INSERT INTO accounts (code, kind, wallet_id, currency)
+SELECT 'wallet:' || id, 'wallet', id, currency FROM user_wallet
+ON CONFLICT DO NOTHING;
+
+INSERT INTO journal_entries (transaction_id, kind, created_at)
+SELECT id, kind, created_at FROM wallet_transactions
+ON CONFLICT DO NOTHING;
+
+CREATE TEMPORARY TABLE counter_accounts (kind VARCHAR(32) PRIMARY KEY, account VARCHAR(32) NOT NULL) ON COMMIT DROP;
+INSERT INTO counter_accounts VALUES
+	('opening', 'adjustments'), ('adjustment', 'adjustments'),
+	('deposit', 'external'), ('withdrawal', 'external'), ('capture', 'external'),
+	('fee', 'fees'), ('interest', 'interest'),
+	('transfer_out', 'clearing'), ('transfer_in', 'clearing'), ('round_up', 'clearing');
+
+INSERT INTO accounts (code, kind, currency)
+SELECT DISTINCT COALESCE(c.account, 'external') || ':' || w.currency, 'system', w.currency
+FROM wallet_transactions t
+JOIN user_wallet w ON w.id = t.wallet_id
+LEFT JOIN counter_accounts c ON c.kind = t.kind
+ON CONFLICT DO NOTHING;
+
+INSERT INTO postings (entry_id, account_id, amount)
+SELECT j.id, a.id, t.amount
+FROM journal_entries j
+JOIN wallet_transactions t ON t.id = j.transaction_id
+JOIN accounts a ON a.wallet_id = t.wallet_id
+UNION ALL
+SELECT j.id, a.id, -t.amount
+FROM journal_entries j
+JOIN wallet_transactions t ON t.id = j.transaction_id
+JOIN user_wallet w ON w.id = t.wallet_id
+LEFT JOIN counter_accounts c ON c.kind = t.kind
+JOIN accounts a ON a.code = COALESCE(c.account, 'external') || ':' || w.currency;
@@ -0,0 +1,20 @@
+-- One report per reconciliation run, with a row for every wallet whose
+-- cached balance differed from the sum of its ledger entries.
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+	id BIGSERIAL PRIMARY KEY,
+	wallets_checked INT NOT NULL,
+	discrepancy_count INT NOT NULL,
+	fix BOOLEAN NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+	report_id BIGINT NOT NULL REFERENCES reconciliation_reports (id) ON DELETE CASCADE,
+	wallet_id INT NOT NULL REFERENCES user_wallet (id) ON DELETE CASCADE,
+	cached NUMERIC(20, 8) NOT NULL,
+	ledger NUMERIC(20, 8) NOT NULL,
+	adjustment_id BIGINT REFERENCES wallet_transactions (id) ON DELETE SET NULL,
+	PRIMARY KEY (report_id, wallet_id)
+);
+
+CREATE INDEX IF NOT EXISTS reconciliation_reports_created_at_idx ON reconciliation_reports (created_at);
@@ -0,0 +1,20 @@
+-- Refunds and reversals move money back from a transfer's destination to
+-- its source as linked compensating entries. refunded_amount caches their
+-- sum so that concurrent refunds can be checked against the transfer under
+-- its row lock.
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS refunded_amount NUMERIC(20, 8) NOT NULL DEFAULT 0;
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP;
+ALTER TABLE transfers ADD CONSTRAINT transfers_refunded_amount_check CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+CREATE TABLE IF NOT EXISTS transfer_refunds (
+	id BIGSERIAL PRIMARY KEY,
+	transfer_id BIGINT NOT NULL REFERENCES transfers (id) ON DELETE CASCADE,
+	amount NUMERIC(20, 8) NOT NULL CHECK (amount > 0),
+	reversal BOOLEAN NOT NULL,
+	reason TEXT,
+	debit_transaction_id BIGINT NOT NULL REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	credit_transaction_id BIGINT NOT NULL REFERENCES wallet_transactions (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS transfer_refunds_transfer_id_idx ON transfer_refunds (transfer_id);
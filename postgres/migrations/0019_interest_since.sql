@@ -0,0 +1,5 @@
+-- When a wallet type last started earning interest. Accrual never backfills
+-- days before it, so a type switched on later does not pay interest for
+-- the time it was off. Existing interest-earning types have always earned.
+ALTER TABLE wallet_types ADD COLUMN IF NOT EXISTS interest_since TIMESTAMP;
+UPDATE wallet_types SET interest_since = created_at WHERE earns_interest;
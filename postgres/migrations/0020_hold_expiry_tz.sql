@@ -0,0 +1,4 @@
+-- Hold expiry is compared with CURRENT_TIMESTAMP, which is a timestamptz:
+-- against a plain TIMESTAMP the result depended on the session's TimeZone.
+-- Expiry times have always been written in UTC.
+ALTER TABLE holds ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC';
@@ -0,0 +1,3 @@
+-- Discrepancies are fixed one wallet at a time; a fix that failed records
+-- why, and the discrepancy is left without an adjustment.
+ALTER TABLE reconciliation_discrepancies ADD COLUMN IF NOT EXISTS error TEXT;
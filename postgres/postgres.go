@@ -1,33 +1,123 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"log"
-	"os"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Postgres struct {
 	Db *sql.DB
+
+	// Observer, when set, is told about every store method call.
+	Observer Observer
+	// Interest holds the interest tiers. Defaults apply when it has none.
+	Interest config.Interest
+	// Crypto holds the price table used to value asset holdings.
+	Crypto config.Crypto
 }
 
-func New() (*Postgres, error) {
-	// databaseSource := fmt.Sprintf("host=%s port=%d user=%s "+
-	// 	"password=%s dbname=%s sslmode=disable", "localhost", 5432, "root", "password", "wallet")
-	err := godotenv.Load(".env")
-	if err != nil {
-		log.Fatal(err)
+func (p *Postgres) interest() config.Interest {
+	if len(p.Interest.Tiers) == 0 {
+		return config.Default().Interest
+	}
+	return p.Interest
+}
+
+// Observer receives the latency and outcome of each store method, e.g. to
+// record metrics.
+type Observer interface {
+	ObserveQuery(method string, elapsed time.Duration, err error)
+}
+
+func (p *Postgres) observe(method string, start time.Time, err *error) {
+	if p.Observer != nil {
+		p.Observer.ObserveQuery(method, time.Since(start), *err)
 	}
-	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+}
+
+func New(cfg config.Database) (*Postgres, error) {
+	db, err := sql.Open("postgres", cfg.URL)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	err = db.Ping()
 	if err != nil {
-		log.Fatal(err)
+		db.Close()
+		return nil, err
 	}
 	return &Postgres{Db: db}, nil
 }
+
+// Stats returns the current connection pool statistics.
+func (p *Postgres) Stats() sql.DBStats {
+	return p.Db.Stats()
+}
+
+// Close closes the connection pool, waiting for queries that have already
+// started to finish.
+func (p *Postgres) Close() error {
+	return p.Db.Close()
+}
+
+// Ping verifies the database is reachable.
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.Db.PingContext(ctx)
+}
+
+// querier is the subset of *sql.DB and *sql.Tx used by the store.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// traced wraps a querier so that every SQL statement gets its own span.
+type traced struct {
+	q querier
+}
+
+func (p *Postgres) db() traced {
+	return traced{q: p.Db}
+}
+
+func statementSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	op, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return tracing.Start(ctx, "postgres "+strings.ToUpper(op),
+		semconv.DBSystemPostgreSQL,
+		semconv.DBStatementKey.String(query),
+	)
+}
+
+func (t traced) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := statementSpan(ctx, query)
+	res, err := t.q.ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (t traced) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := statementSpan(ctx, query)
+	rows, err := t.q.QueryContext(ctx, query, args...)
+	tracing.End(span, err)
+	return rows, err
+}
+
+func (t traced) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := statementSpan(ctx, query)
+	row := t.q.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+	return row
+}
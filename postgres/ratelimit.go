@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/ratelimit"
+)
+
+func (p *Postgres) TakeToken(ctx context.Context, key string, rule ratelimit.Rule, now time.Time) (_ ratelimit.Result, err error) {
+	defer p.observe("TakeToken", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	_, err = q.ExecContext(ctx, "INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING", key, float64(rule.Burst), now)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	var b ratelimit.Bucket
+	err = q.QueryRowContext(ctx, "SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE", key).Scan(&b.Tokens, &b.UpdatedAt)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	res := b.Take(rule, now)
+
+	_, err = q.ExecContext(ctx, "UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1", key, b.Tokens, b.UpdatedAt)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	return res, tx.Commit()
+}
+
+func (p *Postgres) PruneBuckets(ctx context.Context, before time.Time) (err error) {
+	defer p.observe("PruneBuckets", time.Now(), &err)
+
+	_, err = p.db().ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < $1", before)
+	return err
+}
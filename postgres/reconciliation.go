@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// reconciliationReportLimit caps the reports listed.
+const reconciliationReportLimit = 50
+
+// discrepancies selects every wallet whose cached balance differs from the
+// sum of its ledger entries, in id order so that fixing them locks wallets
+// in the same order as transfers do.
+const discrepancies = `SELECT w.id, w.balance, COALESCE(l.ledger, 0)
+	FROM user_wallet w
+	LEFT JOIN (SELECT wallet_id, SUM(amount) AS ledger FROM wallet_transactions GROUP BY wallet_id) l ON l.wallet_id = w.id
+	WHERE w.balance <> COALESCE(l.ledger, 0)
+	ORDER BY w.id`
+
+// Reconcile compares every wallet's cached balance with the sum of its
+// ledger entries and records a report. With fix, each discrepancy is closed
+// by an adjustment entry for the difference, leaving the cached balance as
+// it is. Every wallet is fixed in its own transaction, so a run only holds
+// one wallet's lock at a time and a failed fix is recorded against that
+// wallet without undoing the others.
+func (p *Postgres) Reconcile(ctx context.Context, fix bool) (_ wallet.ReconciliationReport, err error) {
+	defer p.observe("Reconcile", time.Now(), &err)
+
+	report := wallet.ReconciliationReport{Fix: fix, Discrepancies: []wallet.Discrepancy{}}
+	if err := p.findDiscrepancies(ctx, &report); err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+
+	if fix {
+		for i, d := range report.Discrepancies {
+			id, err := p.fixDiscrepancy(ctx, d.WalletID)
+			if err != nil {
+				slog.ErrorContext(ctx, "reconciliation fix failed", "wallet_id", d.WalletID, "error", err)
+				report.Discrepancies[i].Error = err.Error()
+				continue
+			}
+			report.Discrepancies[i].AdjustmentID = id
+		}
+	}
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	err = q.QueryRowContext(ctx, "INSERT INTO reconciliation_reports (wallets_checked, discrepancy_count, fix) VALUES ($1, $2, $3) RETURNING id, created_at",
+		report.WalletsChecked, report.DiscrepancyCount, report.Fix).Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+	for _, d := range report.Discrepancies {
+		_, err = q.ExecContext(ctx, "INSERT INTO reconciliation_discrepancies (report_id, wallet_id, cached, ledger, adjustment_id, error) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))",
+			report.ID, d.WalletID, d.Cached, d.Ledger, d.AdjustmentID, d.Error)
+		if err != nil {
+			return wallet.ReconciliationReport{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+
+	if report.DiscrepancyCount > 0 {
+		slog.WarnContext(ctx, "wallet balances differ from ledger", "report_id", report.ID, "count", report.DiscrepancyCount, "fixed", fix, "failed", report.FailedFixes())
+	}
+	return report, nil
+}
+
+// findDiscrepancies fills in the wallets checked and the discrepancies
+// found, both from one snapshot of the database.
+func (p *Postgres) findDiscrepancies(ctx context.Context, report *wallet.ReconciliationReport) error {
+	tx, err := p.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_wallet").Scan(&report.WalletsChecked); err != nil {
+		return err
+	}
+	rows, err := q.QueryContext(ctx, discrepancies)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d wallet.Discrepancy
+		if err := rows.Scan(&d.WalletID, &d.Cached, &d.Ledger); err != nil {
+			return err
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+	report.DiscrepancyCount = len(report.Discrepancies)
+	return rows.Err()
+}
+
+// fixDiscrepancy runs adjustToBalance for one wallet in its own
+// transaction.
+func (p *Postgres) fixDiscrepancy(ctx context.Context, walletID int) (*int64, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	id, err := adjustToBalance(ctx, traced{q: tx}, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return id, tx.Commit()
+}
+
+// adjustToBalance locks a wallet and, if its ledger still does not sum to
+// its cached balance, records an adjustment entry for the difference. It
+// returns the entry's id, or nil when there was nothing left to adjust.
+func adjustToBalance(ctx context.Context, q traced, walletID int) (*int64, error) {
+	var balance, difference float64
+	err := q.QueryRowContext(ctx, `SELECT w.balance, w.balance - COALESCE((SELECT SUM(amount) FROM wallet_transactions WHERE wallet_id = w.id), 0)
+		FROM user_wallet w WHERE w.id = $1 FOR UPDATE`, walletID).Scan(&balance, &difference)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && difference == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := insertTransaction(ctx, q, wallet.Transaction{
+		WalletID:     walletID,
+		Kind:         wallet.KindAdjustment,
+		Amount:       difference,
+		BalanceAfter: balance,
+		Description:  wallet.ReconciliationDescription,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t.ID, nil
+}
+
+func (p *Postgres) ReconciliationReports(ctx context.Context) (_ []wallet.ReconciliationReport, err error) {
+	defer p.observe("ReconciliationReports", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, "SELECT id, wallets_checked, discrepancy_count, fix, created_at FROM reconciliation_reports ORDER BY id DESC LIMIT $1", reconciliationReportLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []wallet.ReconciliationReport{}
+	for rows.Next() {
+		var r wallet.ReconciliationReport
+		if err := rows.Scan(&r.ID, &r.WalletsChecked, &r.DiscrepancyCount, &r.Fix, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
+
+// ReconciliationReport returns a report together with its discrepancies.
+func (p *Postgres) ReconciliationReport(ctx context.Context, id string) (_ wallet.ReconciliationReport, err error) {
+	defer p.observe("ReconciliationReport", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.ReconciliationReport{}, wallet.ErrReportNotFound
+	}
+	var r wallet.ReconciliationReport
+	err = p.db().QueryRowContext(ctx, "SELECT id, wallets_checked, discrepancy_count, fix, created_at FROM reconciliation_reports WHERE id = $1", id).
+		Scan(&r.ID, &r.WalletsChecked, &r.DiscrepancyCount, &r.Fix, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.ReconciliationReport{}, wallet.ErrReportNotFound
+	}
+	if err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+
+	rows, err := p.db().QueryContext(ctx, "SELECT wallet_id, cached, ledger, adjustment_id, COALESCE(error, '') FROM reconciliation_discrepancies WHERE report_id = $1 ORDER BY wallet_id", r.ID)
+	if err != nil {
+		return wallet.ReconciliationReport{}, err
+	}
+	defer rows.Close()
+
+	r.Discrepancies = []wallet.Discrepancy{}
+	for rows.Next() {
+		var d wallet.Discrepancy
+		var adjustmentID sql.NullInt64
+		if err := rows.Scan(&d.WalletID, &d.Cached, &d.Ledger, &adjustmentID, &d.Error); err != nil {
+			return wallet.ReconciliationReport{}, err
+		}
+		if adjustmentID.Valid {
+			d.AdjustmentID = &adjustmentID.Int64
+		}
+		r.Discrepancies = append(r.Discrepancies, d)
+	}
+	return r, rows.Err()
+}
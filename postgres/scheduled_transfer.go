@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const scheduledTransferColumns = "id, from_wallet_id, to_wallet_id, amount, schedule, starts_at, ends_at, status, next_run_at, retry_at, attempts, failure_count, last_error, last_run_at, created_at, updated_at"
+
+func scanScheduledTransfer(row interface{ Scan(...any) error }) (wallet.ScheduledTransfer, error) {
+	var s wallet.ScheduledTransfer
+	var endsAt, nextRunAt, retryAt, lastRunAt sql.NullTime
+	var lastError sql.NullString
+	err := row.Scan(&s.ID, &s.FromWalletID, &s.ToWalletID, &s.Amount, &s.Schedule, &s.StartsAt, &endsAt,
+		&s.Status, &nextRunAt, &retryAt, &s.Attempts, &s.FailureCount, &lastError, &lastRunAt, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.ScheduledTransfer{}, wallet.ErrScheduledTransferNotFound
+	}
+	if err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	s.EndsAt = timePtr(endsAt)
+	s.NextRunAt = timePtr(nextRunAt)
+	s.RetryAt = timePtr(retryAt)
+	s.LastRunAt = timePtr(lastRunAt)
+	s.LastError = lastError.String
+	return s, nil
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (p *Postgres) CreateScheduledTransfer(ctx context.Context, r wallet.ScheduledTransferRequest) (_ wallet.ScheduledTransfer, err error) {
+	defer p.observe("CreateScheduledTransfer", time.Now(), &err)
+
+	if r.StartsAt.IsZero() {
+		r.StartsAt = time.Now()
+	}
+	r.StartsAt = r.StartsAt.UTC()
+	if r.EndsAt != nil {
+		end := r.EndsAt.UTC()
+		r.EndsAt = &end
+	}
+	first, err := r.FirstRun()
+	if err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	for _, id := range []int{r.FromWalletID, r.ToWalletID} {
+		if err = p.walletExists(ctx, fmt.Sprint(id)); err != nil {
+			return wallet.ScheduledTransfer{}, err
+		}
+	}
+
+	s, err := scanScheduledTransfer(p.db().QueryRowContext(ctx, "INSERT INTO scheduled_transfers (from_wallet_id, to_wallet_id, amount, schedule, starts_at, ends_at, next_run_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "+scheduledTransferColumns,
+		r.FromWalletID, r.ToWalletID, r.Amount, r.Schedule, r.StartsAt, r.EndsAt, first))
+	if err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+
+	slog.InfoContext(ctx, "scheduled transfer created", "scheduled_transfer_id", s.ID, "schedule", s.Schedule, "next_run_at", first)
+	return s, nil
+}
+
+// ScheduledTransfers lists scheduled transfers from or to walletID, or all
+// of them when walletID is empty.
+func (p *Postgres) ScheduledTransfers(ctx context.Context, walletID string) (transfers []wallet.ScheduledTransfer, err error) {
+	defer p.observe("ScheduledTransfers", time.Now(), &err)
+
+	var rows *sql.Rows
+	if walletID == "" {
+		rows, err = p.db().QueryContext(ctx, "SELECT "+scheduledTransferColumns+" FROM scheduled_transfers ORDER BY id")
+	} else {
+		if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+			return nil, wallet.ErrNotFound
+		}
+		rows, err = p.db().QueryContext(ctx, "SELECT "+scheduledTransferColumns+" FROM scheduled_transfers WHERE from_wallet_id = $1 OR to_wallet_id = $1 ORDER BY id", walletID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers = []wallet.ScheduledTransfer{}
+	for rows.Next() {
+		s, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, s)
+	}
+	return transfers, rows.Err()
+}
+
+func (p *Postgres) ScheduledTransfer(ctx context.Context, id string) (_ wallet.ScheduledTransfer, err error) {
+	defer p.observe("ScheduledTransfer", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.ScheduledTransfer{}, wallet.ErrScheduledTransferNotFound
+	}
+	return scanScheduledTransfer(p.db().QueryRowContext(ctx, "SELECT "+scheduledTransferColumns+" FROM scheduled_transfers WHERE id = $1", id))
+}
+
+func (p *Postgres) ScheduledRuns(ctx context.Context, id string) (runs []wallet.ScheduledRun, err error) {
+	defer p.observe("ScheduledRuns", time.Now(), &err)
+
+	if _, err = p.ScheduledTransfer(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := p.db().QueryContext(ctx, "SELECT id, scheduled_transfer_id, scheduled_for, attempt, status, error, transfer_id, created_at FROM scheduled_transfer_runs WHERE scheduled_transfer_id = $1 ORDER BY id DESC", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs = []wallet.ScheduledRun{}
+	for rows.Next() {
+		var r wallet.ScheduledRun
+		var runErr sql.NullString
+		var transferID sql.NullInt64
+		if err = rows.Scan(&r.ID, &r.ScheduledTransferID, &r.ScheduledFor, &r.Attempt, &r.Status, &runErr, &transferID, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		r.Error = runErr.String
+		if transferID.Valid {
+			r.TransferID = &transferID.Int64
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+func (p *Postgres) PauseScheduledTransfer(ctx context.Context, id string) (_ wallet.ScheduledTransfer, err error) {
+	defer p.observe("PauseScheduledTransfer", time.Now(), &err)
+
+	return p.changeSchedule(ctx, id, []string{wallet.ScheduleActive}, func(s *wallet.ScheduledTransfer) error {
+		s.Status = wallet.SchedulePaused
+		return nil
+	})
+}
+
+// ResumeScheduledTransfer reactivates a paused transfer from its next run
+// after now; runs missed while it was paused are not made up.
+func (p *Postgres) ResumeScheduledTransfer(ctx context.Context, id string) (_ wallet.ScheduledTransfer, err error) {
+	defer p.observe("ResumeScheduledTransfer", time.Now(), &err)
+
+	return p.changeSchedule(ctx, id, []string{wallet.SchedulePaused}, func(s *wallet.ScheduledTransfer) error {
+		s.Status = wallet.ScheduleActive
+		s.Attempts, s.RetryAt = 0, nil
+		return advance(s, time.Now())
+	})
+}
+
+func (p *Postgres) CancelScheduledTransfer(ctx context.Context, id string) (_ wallet.ScheduledTransfer, err error) {
+	defer p.observe("CancelScheduledTransfer", time.Now(), &err)
+
+	return p.changeSchedule(ctx, id, []string{wallet.ScheduleActive, wallet.SchedulePaused}, func(s *wallet.ScheduledTransfer) error {
+		s.Status = wallet.ScheduleCancelled
+		s.NextRunAt, s.RetryAt = nil, nil
+		return nil
+	})
+}
+
+// changeSchedule applies change to a scheduled transfer in one of the
+// given statuses and saves its status and run times.
+func (p *Postgres) changeSchedule(ctx context.Context, id string, from []string, change func(*wallet.ScheduledTransfer) error) (wallet.ScheduledTransfer, error) {
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.ScheduledTransfer{}, wallet.ErrScheduledTransferNotFound
+	}
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	s, err := scanScheduledTransfer(q.QueryRowContext(ctx, "SELECT "+scheduledTransferColumns+" FROM scheduled_transfers WHERE id = $1 FOR UPDATE", id))
+	if err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	if !slices.Contains(from, s.Status) {
+		return wallet.ScheduledTransfer{}, fmt.Errorf("%w: %s", wallet.ErrScheduleState, s.Status)
+	}
+	if err = change(&s); err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	if s, err = saveSchedule(ctx, q, s); err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.ScheduledTransfer{}, err
+	}
+
+	slog.InfoContext(ctx, "scheduled transfer changed", "scheduled_transfer_id", s.ID, "status", s.Status)
+	return s, nil
+}
+
+func saveSchedule(ctx context.Context, q traced, s wallet.ScheduledTransfer) (wallet.ScheduledTransfer, error) {
+	var lastError *string
+	if s.LastError != "" {
+		lastError = &s.LastError
+	}
+	return scanScheduledTransfer(q.QueryRowContext(ctx, `UPDATE scheduled_transfers SET status = $2, next_run_at = $3, retry_at = $4, attempts = $5, failure_count = $6, last_error = $7, last_run_at = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 RETURNING `+scheduledTransferColumns,
+		s.ID, s.Status, s.NextRunAt, s.RetryAt, s.Attempts, s.FailureCount, lastError, s.LastRunAt))
+}
+
+// advance moves s to its first run after t, completing it when the
+// schedule has no more runs before its end.
+func advance(s *wallet.ScheduledTransfer, t time.Time) error {
+	sched, err := wallet.ParseSchedule(s.Schedule, s.StartsAt)
+	if err != nil {
+		return err
+	}
+	next := sched.Next(t)
+	if next.IsZero() || s.EndsAt != nil && next.After(*s.EndsAt) {
+		s.Status, s.NextRunAt = wallet.ScheduleCompleted, nil
+		return nil
+	}
+	s.NextRunAt = &next
+	return nil
+}
+
+// RunScheduledTransfers executes scheduled transfers that are due at now,
+// at most cfg.BatchSize of them. Each is claimed with SKIP LOCKED so that
+// several instances can run the worker. A failed run is retried after a
+// growing delay and skipped after cfg.MaxAttempts failures; every attempt
+// is recorded in scheduled_transfer_runs.
+func (p *Postgres) RunScheduledTransfers(ctx context.Context, now time.Time, cfg config.Transfers) (n int, err error) {
+	defer p.observe("RunScheduledTransfers", time.Now(), &err)
+
+	now = now.UTC()
+	for i := 0; i < cfg.BatchSize; i++ {
+		ran, err := p.runScheduledTransfer(ctx, now, cfg)
+		if err != nil {
+			return n, err
+		}
+		if !ran {
+			break
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (p *Postgres) runScheduledTransfer(ctx context.Context, now time.Time, cfg config.Transfers) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	s, err := scanScheduledTransfer(q.QueryRowContext(ctx, "SELECT "+scheduledTransferColumns+" FROM scheduled_transfers WHERE status = $1 AND COALESCE(retry_at, next_run_at) <= $2 ORDER BY COALESCE(retry_at, next_run_at) LIMIT 1 FOR UPDATE SKIP LOCKED",
+		wallet.ScheduleActive, now))
+	if errors.Is(err, wallet.ErrScheduledTransferNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// A failed transfer is rolled back to the savepoint so that the
+	// failure itself can still be recorded.
+	if _, err = q.ExecContext(ctx, "SAVEPOINT transfer"); err != nil {
+		return false, err
+	}
+	t, transferErr := p.transfer(ctx, q, wallet.TransferRequest{FromWalletID: s.FromWalletID, ToWalletID: s.ToWalletID, Amount: s.Amount}, &s.ID)
+	if transferErr != nil {
+		if _, err = q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT transfer"); err != nil {
+			return false, err
+		}
+	}
+
+	run := wallet.ScheduledRun{ScheduledTransferID: s.ID, ScheduledFor: *s.NextRunAt, Attempt: s.Attempts + 1, Status: wallet.RunSucceeded}
+	s.LastRunAt = &now
+	if transferErr == nil {
+		run.TransferID = &t.ID
+		s.Attempts, s.RetryAt, s.LastError = 0, nil, ""
+		err = advance(&s, now)
+	} else {
+		run.Status, run.Error = wallet.RunFailed, transferErr.Error()
+		s.Attempts++
+		s.FailureCount++
+		s.LastError = transferErr.Error()
+		if s.Attempts < cfg.MaxAttempts {
+			retry := now.Add(time.Duration(s.Attempts) * cfg.RetryDelay)
+			s.RetryAt = &retry
+		} else {
+			s.Attempts, s.RetryAt = 0, nil
+			err = advance(&s, now)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = q.ExecContext(ctx, "INSERT INTO scheduled_transfer_runs (scheduled_transfer_id, scheduled_for, attempt, status, error, transfer_id) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)",
+		run.ScheduledTransferID, run.ScheduledFor, run.Attempt, run.Status, run.Error, run.TransferID)
+	if err != nil {
+		return false, err
+	}
+	if _, err = saveSchedule(ctx, q, s); err != nil {
+		return false, err
+	}
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if transferErr != nil {
+		slog.WarnContext(ctx, "scheduled transfer failed", "scheduled_transfer_id", s.ID, "attempt", run.Attempt, "error", transferErr)
+	} else {
+		slog.InfoContext(ctx, "scheduled transfer executed", "scheduled_transfer_id", s.ID, "transfer_id", t.ID, "amount", s.Amount)
+	}
+	return true, nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const statementColumns = "id, wallet_id, period_start, period_end, opening_balance, closing_balance, total_credits, total_debits, minimum_payment, due_date, late_fee, created_at"
+
+func scanStatement(row interface{ Scan(...any) error }) (wallet.Statement, error) {
+	var s wallet.Statement
+	err := row.Scan(&s.ID, &s.WalletID, &s.PeriodStart, &s.PeriodEnd,
+		&s.OpeningBalance, &s.ClosingBalance, &s.TotalCredits, &s.TotalDebits,
+		&s.MinimumPayment, &s.DueDate, &s.LateFee, &s.CreatedAt,
+	)
+	return s, err
+}
+
+func (p *Postgres) Statements(ctx context.Context, walletID string) (statements []wallet.Statement, err error) {
+	defer p.observe("Statements", time.Now(), &err)
+
+	if err = p.walletExists(ctx, walletID); err != nil {
+		return nil, err
+	}
+	rows, err := p.db().QueryContext(ctx, "SELECT "+statementColumns+" FROM statements WHERE wallet_id = $1 ORDER BY period_end DESC", walletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statements = []wallet.Statement{}
+	for rows.Next() {
+		s, err := scanStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+	return statements, rows.Err()
+}
+
+// Statement returns a statement together with the ledger entries of its
+// period.
+func (p *Postgres) Statement(ctx context.Context, walletID, statementID string) (_ wallet.Statement, err error) {
+	defer p.observe("Statement", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(walletID, 10, 32); err != nil {
+		return wallet.Statement{}, wallet.ErrNotFound
+	}
+	if _, err := strconv.ParseInt(statementID, 10, 64); err != nil {
+		return wallet.Statement{}, wallet.ErrStatementNotFound
+	}
+	s, err := scanStatement(p.db().QueryRowContext(ctx, "SELECT "+statementColumns+" FROM statements WHERE wallet_id = $1 AND id = $2", walletID, statementID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Statement{}, wallet.ErrStatementNotFound
+	}
+	if err != nil {
+		return wallet.Statement{}, err
+	}
+
+	rows, err := p.db().QueryContext(ctx, "SELECT "+transactionColumns+" FROM wallet_transactions WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY created_at, id", walletID, s.PeriodStart, s.PeriodEnd)
+	if err != nil {
+		return wallet.Statement{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return wallet.Statement{}, err
+		}
+		s.Transactions = append(s.Transactions, t)
+	}
+	return s, rows.Err()
+}
+
+func (p *Postgres) walletExists(ctx context.Context, id string) error {
+	if _, err := strconv.ParseInt(id, 10, 32); err != nil {
+		return wallet.ErrNotFound
+	}
+	var exists bool
+	err := p.db().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM user_wallet WHERE id = $1)", id).Scan(&exists)
+	if err == nil && !exists {
+		return wallet.ErrNotFound
+	}
+	return err
+}
+
+// GenerateStatements closes the billing period ending at the latest
+// statement day before now for every wallet whose type allows negative
+// balances. A period starts where the wallet's previous statement ended, or
+// when the wallet was opened. Running it again for the same period is a
+// no-op, so several instances may run the scheduler.
+func (p *Postgres) GenerateStatements(ctx context.Context, now time.Time, cfg config.Billing) (n int, err error) {
+	defer p.observe("GenerateStatements", time.Now(), &err)
+
+	end := wallet.StatementPeriodEnd(now, cfg)
+	rows, err := p.db().QueryContext(ctx, `SELECT w.id, COALESCE((SELECT MAX(s.period_end) FROM statements s WHERE s.wallet_id = w.id), w.created_at)
+		FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type
+		WHERE t.allows_negative AND w.created_at < $1
+		AND NOT EXISTS (SELECT 1 FROM statements s WHERE s.wallet_id = w.id AND s.period_end >= $1)`, end)
+	if err != nil {
+		return 0, err
+	}
+	type period struct {
+		walletID int
+		start    time.Time
+	}
+	var periods []period
+	for rows.Next() {
+		var pd period
+		if err = rows.Scan(&pd.walletID, &pd.start); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		periods = append(periods, pd)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, pd := range periods {
+		created, err := p.generateStatement(ctx, pd.walletID, pd.start, end, cfg)
+		if err != nil {
+			return n, fmt.Errorf("wallet %d: %w", pd.walletID, err)
+		}
+		if created {
+			n++
+		}
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "statements generated", "count", n, "period_end", end)
+	}
+	return n, nil
+}
+
+func (p *Postgres) generateStatement(ctx context.Context, walletID int, start, end time.Time, cfg config.Billing) (bool, error) {
+	s := wallet.Statement{WalletID: walletID, PeriodStart: start, PeriodEnd: end, DueDate: wallet.DueDate(end, cfg)}
+
+	var err error
+	if s.OpeningBalance, err = p.balanceBefore(ctx, walletID, start); err != nil {
+		return false, err
+	}
+	if s.ClosingBalance, err = p.balanceBefore(ctx, walletID, end); err != nil {
+		return false, err
+	}
+	err = p.db().QueryRowContext(ctx, `SELECT COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0), COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)
+		FROM wallet_transactions WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3`, walletID, start, end).Scan(&s.TotalCredits, &s.TotalDebits)
+	if err != nil {
+		return false, err
+	}
+	s.MinimumPayment = wallet.MinimumPayment(s.ClosingBalance, cfg)
+
+	res, err := p.db().ExecContext(ctx, `INSERT INTO statements (wallet_id, period_start, period_end, opening_balance, closing_balance, total_credits, total_debits, minimum_payment, due_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (wallet_id, period_end) DO NOTHING`,
+		s.WalletID, s.PeriodStart, s.PeriodEnd, s.OpeningBalance, s.ClosingBalance, s.TotalCredits, s.TotalDebits, s.MinimumPayment, s.DueDate)
+	if err != nil {
+		return false, err
+	}
+	inserted, _ := res.RowsAffected()
+	return inserted > 0, nil
+}
+
+// balanceBefore returns a wallet's balance just before at, according to
+// its ledger.
+func (p *Postgres) balanceBefore(ctx context.Context, walletID int, at time.Time) (float64, error) {
+	var balance float64
+	err := p.db().QueryRowContext(ctx, "SELECT balance_after FROM wallet_transactions WHERE wallet_id = $1 AND created_at < $2 ORDER BY created_at DESC, id DESC LIMIT 1", walletID, at).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return balance, err
+}
+
+// PostLateFees checks every statement whose due date has passed before now
+// and charges the configured late fee when the payments received between
+// the statement's closing and the end of its due date are less than its
+// minimum payment. Each statement is assessed once.
+func (p *Postgres) PostLateFees(ctx context.Context, now time.Time, cfg config.Billing) (n int, err error) {
+	defer p.observe("PostLateFees", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, "SELECT id FROM statements WHERE late_fee_assessed_at IS NULL AND due_date < $1::date", now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		charged, err := p.assessLateFee(ctx, id, cfg)
+		if err != nil {
+			return n, fmt.Errorf("statement %d: %w", id, err)
+		}
+		if charged {
+			n++
+		}
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "late fees posted", "count", n)
+	}
+	return n, nil
+}
+
+func (p *Postgres) assessLateFee(ctx context.Context, id int64, cfg config.Billing) (bool, error) {
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	var s wallet.Statement
+	var assessed sql.NullTime
+	err = q.QueryRowContext(ctx, "SELECT wallet_id, period_end, minimum_payment, due_date, late_fee_assessed_at FROM statements WHERE id = $1 FOR UPDATE", id).
+		Scan(&s.WalletID, &s.PeriodEnd, &s.MinimumPayment, &s.DueDate, &assessed)
+	if err != nil {
+		return false, err
+	}
+	if assessed.Valid {
+		// Another instance got here first.
+		return false, nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT "+transactionColumns+" FROM wallet_transactions WHERE wallet_id = $1 AND amount > 0 AND created_at >= $2 AND created_at < $3::date + 1",
+		s.WalletID, s.PeriodEnd, s.DueDate)
+	if err != nil {
+		return false, err
+	}
+	var credits []wallet.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			rows.Close()
+			return false, err
+		}
+		credits = append(credits, t)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+	paid := wallet.AmountPaid(credits)
+
+	var fee sql.NullInt64
+	late := s.MinimumPayment > 0 && paid < s.MinimumPayment && cfg.LateFee > 0
+	if late {
+		t, err := p.applyChange(ctx, q, fmt.Sprint(s.WalletID), wallet.Transaction{Kind: wallet.KindFee, Amount: -cfg.LateFee})
+		if err != nil {
+			return false, err
+		}
+		s.LateFee, fee = cfg.LateFee, sql.NullInt64{Int64: t.ID, Valid: true}
+	}
+
+	_, err = q.ExecContext(ctx, "UPDATE statements SET late_fee = $2, late_fee_transaction_id = $3, late_fee_assessed_at = CURRENT_TIMESTAMP WHERE id = $1", id, s.LateFee, fee)
+	if err != nil {
+		return false, err
+	}
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if late {
+		slog.InfoContext(ctx, "late fee posted", "statement_id", id, "wallet_id", s.WalletID, "amount", s.LateFee)
+	}
+	return late, nil
+}
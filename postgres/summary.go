@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+// UserSummary totals a user's wallets by currency and by type and currency
+// in one query. The month-over-month change compares balances with those at
+// the start of the month containing now, taken from snapshots and the
+// ledger as balancesAt does.
+func (p *Postgres) UserSummary(ctx context.Context, userID string, now time.Time) (_ wallet.UserSummary, err error) {
+	defer p.observe("UserSummary", time.Now(), &err)
+
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return wallet.UserSummary{}, wallet.ErrNotFound
+	}
+	now = now.UTC()
+	s := wallet.UserSummary{
+		UserID:      id,
+		MonthStart:  time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		Currencies:  []wallet.CurrencySummary{},
+		WalletTypes: []wallet.TypeSummary{},
+	}
+
+	rows, err := p.db().QueryContext(ctx, `WITH w AS (
+			SELECT w.wallet_type, w.currency, w.balance,
+				COALESCE(s.balance, 0) + COALESCE((SELECT SUM(t.amount) FROM wallet_transactions t
+					WHERE t.wallet_id = w.id AND t.created_at >= COALESCE(s.snapshot_date + 1, '-infinity'::date) AND t.created_at < $2::timestamp), 0) AS month_start
+			FROM user_wallet w
+			LEFT JOIN LATERAL (SELECT snapshot_date, balance FROM balance_snapshots
+				WHERE wallet_id = w.id AND snapshot_date + 1 <= $2::timestamp ORDER BY snapshot_date DESC LIMIT 1) s ON TRUE
+			WHERE w.user_id = $1
+		)
+		SELECT currency, wallet_type, COUNT(*),
+			COALESCE(SUM(GREATEST(balance, 0)), 0), COALESCE(-SUM(LEAST(balance, 0)), 0),
+			COALESCE(SUM(balance), 0), COALESCE(SUM(month_start), 0),
+			COALESCE(SUM(balance) - SUM(month_start), 0),
+			ROUND((SUM(balance) - SUM(month_start)) / NULLIF(ABS(SUM(month_start)), 0) * 100, 1)
+		FROM w
+		GROUP BY GROUPING SETS ((), (currency), (currency, wallet_type))
+		ORDER BY currency NULLS FIRST, wallet_type NULLS FIRST`, id, s.MonthStart)
+	if err != nil {
+		return wallet.UserSummary{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c wallet.CurrencySummary
+		var currency, walletType sql.NullString
+		var percent sql.NullFloat64
+		err = rows.Scan(&currency, &walletType, &c.WalletCount, &c.Assets, &c.Debt, &c.NetWorth, &c.MonthStartNetWorth, &c.MonthChange, &percent)
+		if err != nil {
+			return wallet.UserSummary{}, err
+		}
+		c.Currency = currency.String
+		// The grouping sets give one grand total row, then per currency
+		// a total row without a type followed by one row per type.
+		switch {
+		case !currency.Valid:
+			s.WalletCount = c.WalletCount
+		case walletType.Valid:
+			s.WalletTypes = append(s.WalletTypes, wallet.TypeSummary{WalletType: walletType.String, Currency: c.Currency, Count: c.WalletCount, Balance: c.NetWorth})
+		default:
+			if percent.Valid {
+				c.MonthChangePercent = &percent.Float64
+			}
+			s.Currencies = append(s.Currencies, c)
+		}
+	}
+	return s, rows.Err()
+}
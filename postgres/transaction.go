@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+func (p *Postgres) Deposit(ctx context.Context, id string, r wallet.AmountRequest) (_ wallet.Transaction, err error) {
+	defer p.observe("Deposit", time.Now(), &err)
+
+	return p.move(ctx, id, r.Transaction(wallet.KindDeposit, r.Amount))
+}
+
+func (p *Postgres) Withdraw(ctx context.Context, id string, r wallet.AmountRequest) (_ wallet.Transaction, err error) {
+	defer p.observe("Withdraw", time.Now(), &err)
+
+	return p.move(ctx, id, r.Transaction(wallet.KindWithdrawal, -r.Amount))
+}
+
+// move applies the signed amount of t to a wallet and records it in the
+// ledger in one transaction. An entry without a category is categorised by
+// the category rules first.
+func (p *Postgres) move(ctx context.Context, id string, t wallet.Transaction) (wallet.Transaction, error) {
+	if t.Category == "" && (t.Merchant != "" || t.Description != "") {
+		rules, err := categoryRules(ctx, p.db())
+		if err != nil {
+			return wallet.Transaction{}, err
+		}
+		t.Category = wallet.Categorize(rules, t.Merchant, t.Description)
+	}
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Transaction{}, err
+	}
+	defer tx.Rollback()
+
+	t, err = p.applyChange(ctx, traced{q: tx}, id, t)
+	if err != nil {
+		return wallet.Transaction{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return wallet.Transaction{}, err
+	}
+
+	slog.DebugContext(ctx, "wallet balance changed", "wallet_id", t.WalletID, "kind", t.Kind, "amount", t.Amount, "balance", t.BalanceAfter)
+	return t, nil
+}
+
+// applyChange changes a wallet's balance by the signed amount of t and
+// records t as a ledger entry, within the caller's transaction. The wallet
+// row is locked first so that the policy check and the update see the same
+// balance. Spending debits are then checked against the wallet's budgets
+// and rounded up into savings goals.
+func (p *Postgres) applyChange(ctx context.Context, q traced, id string, t wallet.Transaction) (wallet.Transaction, error) {
+	if err := p.checkChange(ctx, q, id, t.Kind, t.Amount); err != nil {
+		return wallet.Transaction{}, err
+	}
+
+	var userID int
+	err := q.QueryRowContext(ctx, "UPDATE user_wallet SET balance = balance + $2 WHERE id = $1 RETURNING id, user_id, balance", id, t.Amount).Scan(&t.WalletID, &userID, &t.BalanceAfter)
+	if err != nil {
+		return wallet.Transaction{}, err
+	}
+	t, err = insertTransaction(ctx, q, t)
+	if err != nil {
+		return wallet.Transaction{}, err
+	}
+	if err := checkBudgets(ctx, q, userID, t); err != nil {
+		return wallet.Transaction{}, err
+	}
+	if err := p.roundUp(ctx, q, t); err != nil {
+		return wallet.Transaction{}, err
+	}
+	return t, nil
+}
+
+// checkChange locks the wallet row and applies its type's policy to a
+// proposed signed change of amount. Every balance-changing operation must
+// call it inside its transaction before updating the balance.
+func (p *Postgres) checkChange(ctx context.Context, q traced, id string, kind string, amount float64) error {
+	if _, err := strconv.ParseInt(id, 10, 32); err != nil {
+		return wallet.ErrNotFound
+	}
+	var typ wallet.Type
+	var ownLimit sql.NullFloat64
+	ch := wallet.Change{Kind: kind, Amount: amount}
+	err := q.QueryRowContext(ctx, `SELECT w.balance, w.credit_limit, t.allows_negative, t.decimals, t.default_credit_limit, t.monthly_withdrawal_cap
+		FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type
+		WHERE w.id = $1 FOR UPDATE OF w`, id).Scan(&ch.Balance, &ownLimit, &typ.AllowsNegative, &typ.Decimals, &typ.DefaultCreditLimit, &typ.MonthlyWithdrawalCap)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	ch.CreditLimit = ownLimit.Float64
+
+	// Funds reserved by holds are not available to other debits.
+	held, err := heldAmount(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	ch.Balance -= held
+
+	policy := typ.Policy()
+	if policy.MonthlyWithdrawalCap > 0 && amount < 0 {
+		err = q.QueryRowContext(ctx, "SELECT COALESCE(-SUM(amount), 0) FROM wallet_transactions WHERE wallet_id = $1 AND kind = ANY($2) AND amount < 0 AND created_at >= date_trunc('month', CURRENT_TIMESTAMP)", id, pq.Array(wallet.WithdrawalKinds)).Scan(&ch.WithdrawnThisMonth)
+		if err != nil {
+			return err
+		}
+	}
+	return policy.Check(ch)
+}
+
+// insertTransaction records a ledger entry whose balance change has already
+// been applied to user_wallet within the same database transaction, and
+// posts it to the journal.
+func insertTransaction(ctx context.Context, q traced, t wallet.Transaction) (wallet.Transaction, error) {
+	err := q.QueryRowContext(ctx, `INSERT INTO wallet_transactions (wallet_id, kind, amount, balance_after, category, merchant, description, tags)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8) RETURNING id, created_at`,
+		t.WalletID, t.Kind, t.Amount, t.BalanceAfter, t.Category, t.Merchant, t.Description, pq.Array(nonNil(t.Tags))).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return wallet.Transaction{}, categoryError(err)
+	}
+	return t, postJournal(ctx, q, t)
+}
+
+// transactionColumns are the columns scanned by scanTransaction.
+const transactionColumns = "id, wallet_id, kind, amount, balance_after, COALESCE(category, ''), COALESCE(merchant, ''), COALESCE(description, ''), tags, created_at"
+
+func scanTransaction(row interface{ Scan(...any) error }) (wallet.Transaction, error) {
+	var t wallet.Transaction
+	err := row.Scan(&t.ID, &t.WalletID, &t.Kind, &t.Amount, &t.BalanceAfter, &t.Category, &t.Merchant, &t.Description, pq.Array(&t.Tags), &t.CreatedAt)
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	return t, err
+}
+
+func nonNil(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const transferColumns = "id, from_wallet_id, to_wallet_id, amount, debit_transaction_id, credit_transaction_id, scheduled_transfer_id, refunded_amount, reversed_at, created_at"
+
+func scanTransfer(row interface{ Scan(...any) error }) (wallet.Transfer, error) {
+	var t wallet.Transfer
+	var scheduledID sql.NullInt64
+	var reversedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.FromWalletID, &t.ToWalletID, &t.Amount, &t.DebitTransactionID, &t.CreditTransactionID, &scheduledID, &t.RefundedAmount, &reversedAt, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Transfer{}, wallet.ErrTransferNotFound
+	}
+	if scheduledID.Valid {
+		t.ScheduledTransferID = &scheduledID.Int64
+	}
+	if reversedAt.Valid {
+		t.ReversedAt = &reversedAt.Time
+	}
+	t.Status = wallet.TransferStatus(t.Amount, t.RefundedAmount, t.ReversedAt != nil)
+	return t, err
+}
+
+const refundColumns = "id, transfer_id, amount, reversal, COALESCE(reason, ''), debit_transaction_id, credit_transaction_id, created_at"
+
+func scanRefund(row interface{ Scan(...any) error }) (wallet.TransferRefund, error) {
+	var r wallet.TransferRefund
+	err := row.Scan(&r.ID, &r.TransferID, &r.Amount, &r.Reversal, &r.Reason, &r.DebitTransactionID, &r.CreditTransactionID, &r.CreatedAt)
+	return r, err
+}
+
+func (p *Postgres) CreateTransfer(ctx context.Context, r wallet.TransferRequest) (_ wallet.Transfer, err error) {
+	defer p.observe("CreateTransfer", time.Now(), &err)
+
+	if err = r.Validate(); err != nil {
+		return wallet.Transfer{}, err
+	}
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	defer tx.Rollback()
+
+	t, err := p.transfer(ctx, traced{q: tx}, r, nil)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Transfer{}, err
+	}
+
+	slog.DebugContext(ctx, "transfer created", "transfer_id", t.ID, "from_wallet_id", t.FromWalletID, "to_wallet_id", t.ToWalletID, "amount", t.Amount)
+	return t, nil
+}
+
+// transfer debits and credits the two wallets within the caller's
+// transaction.
+func (p *Postgres) transfer(ctx context.Context, q traced, r wallet.TransferRequest, scheduledID *int64) (wallet.Transfer, error) {
+	if err := lockWalletPair(ctx, q, r.FromWalletID, r.ToWalletID); err != nil {
+		return wallet.Transfer{}, err
+	}
+
+	debit, err := p.applyChange(ctx, q, strconv.Itoa(r.FromWalletID), wallet.Transaction{Kind: wallet.KindTransferOut, Amount: -r.Amount})
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	credit, err := p.applyChange(ctx, q, strconv.Itoa(r.ToWalletID), wallet.Transaction{Kind: wallet.KindTransferIn, Amount: r.Amount})
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	return scanTransfer(q.QueryRowContext(ctx, "INSERT INTO transfers (from_wallet_id, to_wallet_id, amount, debit_transaction_id, credit_transaction_id, scheduled_transfer_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+transferColumns,
+		r.FromWalletID, r.ToWalletID, r.Amount, debit.ID, credit.ID, scheduledID))
+}
+
+// lockWalletPair locks both wallet rows of a transfer in id order, so that
+// opposite transfers between the same wallets cannot deadlock, and checks
+// that they hold the same currency.
+func lockWalletPair(ctx context.Context, q traced, a, b int) error {
+	rows, err := q.QueryContext(ctx, "SELECT currency FROM user_wallet WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", a, b)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var currencies []string
+	for rows.Next() {
+		var currency string
+		if err = rows.Scan(&currency); err != nil {
+			return err
+		}
+		currencies = append(currencies, currency)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(currencies) != 2 {
+		return wallet.ErrNotFound
+	}
+	if currencies[0] != currencies[1] {
+		return wallet.ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// Transfer returns a transfer together with its refunds.
+func (p *Postgres) Transfer(ctx context.Context, id string) (_ wallet.Transfer, err error) {
+	defer p.observe("Transfer", time.Now(), &err)
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.Transfer{}, wallet.ErrTransferNotFound
+	}
+	t, err := scanTransfer(p.db().QueryRowContext(ctx, "SELECT "+transferColumns+" FROM transfers WHERE id = $1", id))
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	t.Refunds, err = transferRefunds(ctx, p.db(), t.ID)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	return t, nil
+}
+
+func transferRefunds(ctx context.Context, q traced, transferID int64) ([]wallet.TransferRefund, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+refundColumns+" FROM transfer_refunds WHERE transfer_id = $1 ORDER BY id", transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []wallet.TransferRefund{}
+	for rows.Next() {
+		r, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, r)
+	}
+	return refunds, rows.Err()
+}
+
+func (p *Postgres) RefundTransfer(ctx context.Context, id string, r wallet.RefundRequest) (_ wallet.Transfer, err error) {
+	defer p.observe("RefundTransfer", time.Now(), &err)
+
+	return p.refund(ctx, id, r, false)
+}
+
+func (p *Postgres) ReverseTransfer(ctx context.Context, id string, r wallet.RefundRequest) (_ wallet.Transfer, err error) {
+	defer p.observe("ReverseTransfer", time.Now(), &err)
+
+	return p.refund(ctx, id, r, true)
+}
+
+// refund moves money back from a transfer's destination to its source in
+// one transaction: a partial refund of r.Amount, or with reversal whatever
+// has not been refunded yet. The transfer row is locked before the wallets,
+// so that concurrent refunds are checked against each other's totals.
+func (p *Postgres) refund(ctx context.Context, id string, r wallet.RefundRequest, reversal bool) (wallet.Transfer, error) {
+	if err := r.Validate(reversal); err != nil {
+		return wallet.Transfer{}, err
+	}
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return wallet.Transfer{}, wallet.ErrTransferNotFound
+	}
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	t, err := scanTransfer(q.QueryRowContext(ctx, "SELECT "+transferColumns+" FROM transfers WHERE id = $1 FOR UPDATE", id))
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	if t.Status == wallet.TransferRefunded || t.Status == wallet.TransferReversed {
+		return wallet.Transfer{}, wallet.ErrTransferRefunded
+	}
+	// Compare in NUMERIC: the remaining amount is rarely exact in float64.
+	var remaining float64
+	var fits bool
+	err = q.QueryRowContext(ctx, "SELECT amount - refunded_amount, $2::numeric <= amount - refunded_amount FROM transfers WHERE id = $1", id, r.Amount).Scan(&remaining, &fits)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	if reversal {
+		r.Amount = remaining
+	} else if !fits {
+		return wallet.Transfer{}, wallet.ErrRefundExceedsAmount
+	}
+
+	if err := lockWalletPair(ctx, q, t.FromWalletID, t.ToWalletID); err != nil {
+		return wallet.Transfer{}, err
+	}
+	description := wallet.RefundDescription(t.ID, reversal)
+	debit, err := p.applyChange(ctx, q, strconv.Itoa(t.ToWalletID), wallet.Transaction{Kind: wallet.KindRefundOut, Amount: -r.Amount, Description: description})
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	credit, err := p.applyChange(ctx, q, strconv.Itoa(t.FromWalletID), wallet.Transaction{Kind: wallet.KindRefundIn, Amount: r.Amount, Description: description})
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	_, err = q.ExecContext(ctx, "INSERT INTO transfer_refunds (transfer_id, amount, reversal, reason, debit_transaction_id, credit_transaction_id) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)",
+		t.ID, r.Amount, reversal, r.Reason, debit.ID, credit.ID)
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	t, err = scanTransfer(q.QueryRowContext(ctx, `UPDATE transfers SET refunded_amount = refunded_amount + $2, reversed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE reversed_at END
+		WHERE id = $1 RETURNING `+transferColumns, t.ID, r.Amount, reversal))
+	if err != nil {
+		return wallet.Transfer{}, err
+	}
+	if t.Refunds, err = transferRefunds(ctx, q, t.ID); err != nil {
+		return wallet.Transfer{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return wallet.Transfer{}, err
+	}
+
+	slog.DebugContext(ctx, "transfer refunded", "transfer_id", t.ID, "amount", r.Amount, "reversal", reversal, "status", t.Status)
+	return t, nil
+}
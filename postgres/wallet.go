@@ -1,7 +1,11 @@
 package postgres
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -9,138 +13,232 @@ import (
 )
 
 type Wallet struct {
-	ID         int       `postgres:"id"`
-	UserID     int       `postgres:"user_id"`
-	UserName   string    `postgres:"user_name"`
-	WalletName string    `postgres:"wallet_name"`
-	WalletType string    `postgres:"wallet_type"`
-	Balance    float64   `postgres:"balance"`
-	CreatedAt  time.Time `postgres:"created_at"`
+	ID          int             `postgres:"id"`
+	UserID      int             `postgres:"user_id"`
+	UserName    string          `postgres:"user_name"`
+	WalletName  string          `postgres:"wallet_name"`
+	WalletType  string          `postgres:"wallet_type"`
+	Currency    string          `postgres:"currency"`
+	Balance     float64         `postgres:"balance"`
+	CreditLimit sql.NullFloat64 `postgres:"credit_limit"`
+	CreatedAt   time.Time       `postgres:"created_at"`
 }
 
-type Err struct {
-	Message string `json:"message"`
-}
+// selectWallets selects wallets together with the type attributes needed
+// to compute their effective credit limit and the total of their holds.
+const selectWallets = `SELECT w.id, w.user_id, w.user_name, w.wallet_name, w.wallet_type, w.currency, w.balance, w.credit_limit, w.created_at,
+	t.allows_negative, t.default_credit_limit, ` + heldSubquery + `
+FROM user_wallet w JOIN wallet_types t ON t.name = w.wallet_type`
 
-func (p *Postgres) Wallets() ([]wallet.Wallet, error) {
-	rows, err := p.Db.Query("SELECT * FROM user_wallet")
-	if err != nil {
-		return nil, err
-	}
+// scanWallets reads rows selected with selectWallets.
+func (p *Postgres) scanWallets(rows *sql.Rows) ([]wallet.Wallet, error) {
 	defer rows.Close()
 
 	var wallets []wallet.Wallet
 	for rows.Next() {
 		var w Wallet
+		var policy wallet.Policy
+		var held float64
 		err := rows.Scan(&w.ID,
 			&w.UserID, &w.UserName,
-			&w.WalletName, &w.WalletType,
-			&w.Balance, &w.CreatedAt,
+			&w.WalletName, &w.WalletType, &w.Currency,
+			&w.Balance, &w.CreditLimit, &w.CreatedAt,
+			&policy.AllowsNegative, &policy.DefaultCreditLimit, &held,
 		)
 		if err != nil {
 			return nil, err
 		}
 		wallets = append(wallets, wallet.Wallet{
-			ID:         w.ID,
-			UserID:     w.UserID,
-			UserName:   w.UserName,
-			WalletName: w.WalletName,
-			WalletType: w.WalletType,
-			Balance:    w.Balance,
-			CreatedAt:  w.CreatedAt,
+			ID:               w.ID,
+			UserID:           w.UserID,
+			UserName:         w.UserName,
+			WalletName:       w.WalletName,
+			WalletType:       w.WalletType,
+			Currency:         w.Currency,
+			Balance:          w.Balance,
+			AvailableBalance: w.Balance - held,
+			CreditLimit:      policy.CreditLimit(w.CreditLimit.Float64),
+			CreatedAt:        w.CreatedAt,
 		})
 	}
-	return wallets, nil
+	return wallets, rows.Err()
 }
 
-func (p *Postgres) WalletsByUser(id string) ([]wallet.Wallet, error) {
-	rows, err := p.Db.Query("SELECT * FROM user_wallet WHERE user_id = $1", id)
+func (p *Postgres) Wallets(ctx context.Context) (wallets []wallet.Wallet, err error) {
+	defer p.observe("Wallets", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, selectWallets+" ORDER BY w.id")
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return p.scanWallets(rows)
+}
 
-	var wallets []wallet.Wallet
-	for rows.Next() {
-		var w Wallet
-		err := rows.Scan(&w.ID,
-			&w.UserID, &w.UserName,
-			&w.WalletName, &w.WalletType,
-			&w.Balance, &w.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		wallets = append(wallets, wallet.Wallet{
-			ID:         w.ID,
-			UserID:     w.UserID,
-			UserName:   w.UserName,
-			WalletName: w.WalletName,
-			WalletType: w.WalletType,
-			Balance:    w.Balance,
-			CreatedAt:  w.CreatedAt,
-		})
+func (p *Postgres) WalletsByUser(ctx context.Context, id string) (wallets []wallet.Wallet, err error) {
+	defer p.observe("WalletsByUser", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, selectWallets+" WHERE w.user_id = $1 ORDER BY w.id", id)
+	if err != nil {
+		return nil, err
 	}
-	return wallets, nil
+	return p.scanWallets(rows)
 }
 
-func (p *Postgres) WalletsQuery(wallet_type string) ([]wallet.Wallet, error) {
-	rows, err := p.Db.Query("SELECT * FROM user_wallet WHERE wallet_type = $1", wallet_type)
+func (p *Postgres) WalletsQuery(ctx context.Context, wallet_type string) (wallets []wallet.Wallet, err error) {
+	defer p.observe("WalletsQuery", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, selectWallets+" WHERE w.wallet_type = $1 ORDER BY w.id", wallet_type)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return p.scanWallets(rows)
+}
 
-	var wallets []wallet.Wallet
-	for rows.Next() {
-		var w Wallet
-		err := rows.Scan(&w.ID,
-			&w.UserID, &w.UserName,
-			&w.WalletName, &w.WalletType,
-			&w.Balance, &w.CreatedAt,
-		)
+func (p *Postgres) CreateWallet(ctx context.Context, w wallet.Wallet) (_ wallet.Wallet, err error) {
+	defer p.observe("CreateWallet", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	if w.Currency, err = wallet.NormalizeCurrency(w.Currency); err != nil {
+		return wallet.Wallet{}, err
+	}
+	typ, err := walletType(ctx, q, w.WalletType)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	if typ.RetiredAt != nil {
+		return wallet.Wallet{}, wallet.ErrTypeRetired
+	}
+	policy := typ.Policy()
+	if err = policy.Check(wallet.Change{Kind: wallet.KindOpening, Amount: w.Balance, CreditLimit: w.CreditLimit}); err != nil {
+		return wallet.Wallet{}, err
+	}
+
+	row := q.QueryRowContext(ctx, "INSERT INTO user_wallet (user_id, user_name, wallet_name, wallet_type, balance, credit_limit, currency) values ($1, $2, $3, $4, $5, NULLIF($6, 0), $7) RETURNING id, created_at", w.UserID, w.UserName, w.WalletName, w.WalletType, w.Balance, w.CreditLimit, w.Currency)
+	err = row.Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	w.CreditLimit = policy.CreditLimit(w.CreditLimit)
+	w.AvailableBalance = w.Balance
+	if w.Balance != 0 {
+		_, err = insertTransaction(ctx, q, wallet.Transaction{WalletID: w.ID, Kind: wallet.KindOpening, Amount: w.Balance, BalanceAfter: w.Balance})
 		if err != nil {
-			return nil, err
+			return wallet.Wallet{}, err
 		}
-		wallets = append(wallets, wallet.Wallet{
-			ID:         w.ID,
-			UserID:     w.UserID,
-			UserName:   w.UserName,
-			WalletName: w.WalletName,
-			WalletType: w.WalletType,
-			Balance:    w.Balance,
-			CreatedAt:  w.CreatedAt,
-		})
 	}
-	return wallets, nil
+	if err = tx.Commit(); err != nil {
+		return wallet.Wallet{}, err
+	}
+
+	slog.DebugContext(ctx, "wallet created", "wallet_id", w.ID, "user_id", w.UserID, "user_name", w.UserName, "balance", w.Balance)
+	return w, nil
 }
 
-func (p *Postgres) CreateWallet(w wallet.Wallet) (wallet.Wallet, error) {
-	row := p.Db.QueryRow("INSERT INTO user_wallet (user_id, user_name, wallet_name, wallet_type, balance) values ($1, $2, $3, $4, $5) RETURNING id", w.UserID, w.UserName, w.WalletName, w.WalletType, w.Balance)
-	err := row.Scan(&w.ID)
+// UpdateWallet replaces a wallet's fields. A change of balance is recorded
+// in the ledger as an adjustment so that the ledger still explains it.
+func (p *Postgres) UpdateWallet(ctx context.Context, w wallet.Wallet, id string) (_ wallet.Wallet, err error) {
+	defer p.observe("UpdateWallet", time.Now(), &err)
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	defer tx.Rollback()
+	q := traced{q: tx}
+
+	var previous float64
+	var previousType string
+	var ownLimit sql.NullFloat64
+	var currency string
+	err = q.QueryRowContext(ctx, "SELECT wallet_type, currency, balance, credit_limit FROM user_wallet WHERE id = $1 FOR UPDATE", id).Scan(&previousType, &currency, &previous, &ownLimit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Wallet{}, wallet.ErrNotFound
+	}
 	if err != nil {
-		log.Fatal(err)
+		return wallet.Wallet{}, err
+	}
+	if w.Currency != "" && !strings.EqualFold(strings.TrimSpace(w.Currency), currency) {
+		return wallet.Wallet{}, wallet.ErrCurrencyChange
 	}
+	w.Currency = currency
 
-	return w, err
-}
+	// A wallet keeps its own credit limit unless the update sets a new one.
+	if w.CreditLimit == 0 {
+		w.CreditLimit = ownLimit.Float64
+	}
+	typ, err := walletType(ctx, q, w.WalletType)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	// Wallets may keep a retired type but not move to one.
+	if typ.RetiredAt != nil && typ.Name != previousType {
+		return wallet.Wallet{}, wallet.ErrTypeRetired
+	}
+	held, err := heldAmount(ctx, q, id)
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
+	policy := typ.Policy()
+	err = policy.Check(wallet.Change{Kind: wallet.KindAdjustment, Amount: w.Balance - previous, Balance: previous - held, CreditLimit: w.CreditLimit})
+	if err != nil {
+		return wallet.Wallet{}, err
+	}
 
-func (p *Postgres) UpdateWallet(w wallet.Wallet, id string) (wallet.Wallet, error) {
-	row := p.Db.QueryRow("UPDATE user_wallet SET user_id = $2, user_name = $3, wallet_name = $4, wallet_type = $5, balance = $6 WHERE id = $1 RETURNING id", id, w.UserID, w.UserName, w.WalletName, w.WalletType, w.Balance)
-	err := row.Scan(&id)
+	row := q.QueryRowContext(ctx, "UPDATE user_wallet SET user_id = $2, user_name = $3, wallet_name = $4, wallet_type = $5, balance = $6, credit_limit = NULLIF($7, 0) WHERE id = $1 RETURNING id, created_at", id, w.UserID, w.UserName, w.WalletName, w.WalletType, w.Balance, w.CreditLimit)
+	err = row.Scan(&w.ID, &w.CreatedAt)
 	if err != nil {
-		log.Fatal(err)
+		return wallet.Wallet{}, err
+	}
+	w.CreditLimit = policy.CreditLimit(w.CreditLimit)
+	w.AvailableBalance = w.Balance - held
+	if diff := w.Balance - previous; diff != 0 {
+		_, err = insertTransaction(ctx, q, wallet.Transaction{WalletID: w.ID, Kind: wallet.KindAdjustment, Amount: diff, BalanceAfter: w.Balance})
+		if err != nil {
+			return wallet.Wallet{}, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return wallet.Wallet{}, err
 	}
 
+	slog.DebugContext(ctx, "wallet updated", "wallet_id", w.ID, "user_id", w.UserID, "user_name", w.UserName, "balance", w.Balance)
 	return w, nil
 }
 
-func (p *Postgres) DeleteWallet(id string) error {
-	row, err := p.Db.Query("DELETE FROM user_wallet WHERE user_id = $1", id)
-	row.Scan(&id)
+func (p *Postgres) DeleteWallet(ctx context.Context, id string) (err error) {
+	defer p.observe("DeleteWallet", time.Now(), &err)
+
+	res, err := p.db().ExecContext(ctx, "DELETE FROM user_wallet WHERE user_id = $1", id)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	n, _ := res.RowsAffected()
+	slog.DebugContext(ctx, "wallets deleted", "user_id", id, "count", n)
 	return nil
 }
+
+func (p *Postgres) WalletTotalsByType(ctx context.Context) (totals []wallet.TypeTotal, err error) {
+	defer p.observe("WalletTotalsByType", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, "SELECT wallet_type, COUNT(*), COALESCE(SUM(balance), 0) FROM user_wallet GROUP BY wallet_type ORDER BY wallet_type")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t wallet.TypeTotal
+		if err = rows.Scan(&t.WalletType, &t.Count, &t.Balance); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openmymai/fun-exercise-api/wallet"
+)
+
+const walletTypeColumns = "name, allows_negative, decimals, default_credit_limit, monthly_withdrawal_cap, earns_interest, interest_since, holds_assets, allows_goals, retired_at, created_at"
+
+func scanWalletType(row interface{ Scan(...any) error }) (wallet.Type, error) {
+	var t wallet.Type
+	var interestSince, retiredAt sql.NullTime
+	err := row.Scan(&t.Name, &t.AllowsNegative, &t.Decimals, &t.DefaultCreditLimit, &t.MonthlyWithdrawalCap, &t.EarnsInterest, &interestSince, &t.HoldsAssets, &t.AllowsGoals, &retiredAt, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return wallet.Type{}, wallet.ErrTypeNotFound
+	}
+	if interestSince.Valid {
+		t.InterestSince = &interestSince.Time
+	}
+	if retiredAt.Valid {
+		t.RetiredAt = &retiredAt.Time
+	}
+	return t, err
+}
+
+// walletType loads a wallet type by name. It is used by wallet writes to
+// apply the type's rules, so an unknown name is the caller's mistake.
+func walletType(ctx context.Context, q querier, name string) (wallet.Type, error) {
+	t, err := scanWalletType(q.QueryRowContext(ctx, "SELECT "+walletTypeColumns+" FROM wallet_types WHERE name = $1", name))
+	if errors.Is(err, wallet.ErrTypeNotFound) {
+		return wallet.Type{}, wallet.ErrUnknownWalletType
+	}
+	return t, err
+}
+
+func (p *Postgres) WalletTypes(ctx context.Context) (types []wallet.Type, err error) {
+	defer p.observe("WalletTypes", time.Now(), &err)
+
+	rows, err := p.db().QueryContext(ctx, "SELECT "+walletTypeColumns+" FROM wallet_types ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanWalletType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, rows.Err()
+}
+
+func (p *Postgres) CreateWalletType(ctx context.Context, t wallet.Type) (_ wallet.Type, err error) {
+	defer p.observe("CreateWalletType", time.Now(), &err)
+
+	if err = t.Validate(); err != nil {
+		return wallet.Type{}, err
+	}
+	row := p.db().QueryRowContext(ctx, "INSERT INTO wallet_types (name, allows_negative, decimals, default_credit_limit, monthly_withdrawal_cap, earns_interest, interest_since, holds_assets, allows_goals) VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $6 THEN CURRENT_TIMESTAMP END, $7, $8) RETURNING "+walletTypeColumns,
+		t.Name, t.AllowsNegative, t.Decimals, t.DefaultCreditLimit, t.MonthlyWithdrawalCap, t.EarnsInterest, t.HoldsAssets, t.AllowsGoals)
+	t, err = scanWalletType(row)
+	if err = typeError(err); err != nil {
+		return wallet.Type{}, err
+	}
+
+	slog.InfoContext(ctx, "wallet type created", "wallet_type", t.Name)
+	return t, nil
+}
+
+// UpdateWalletType replaces the attributes of the type called name and
+// renames it to t.Name. Wallets follow the rename through the foreign key.
+func (p *Postgres) UpdateWalletType(ctx context.Context, name string, t wallet.Type) (_ wallet.Type, err error) {
+	defer p.observe("UpdateWalletType", time.Now(), &err)
+
+	if err = t.Validate(); err != nil {
+		return wallet.Type{}, err
+	}
+	row := p.db().QueryRowContext(ctx, "UPDATE wallet_types SET name = $2, allows_negative = $3, decimals = $4, default_credit_limit = $5, monthly_withdrawal_cap = $6, earns_interest = $7, interest_since = CASE WHEN NOT $7 THEN NULL WHEN earns_interest THEN interest_since ELSE CURRENT_TIMESTAMP END, holds_assets = $8, allows_goals = $9 WHERE name = $1 RETURNING "+walletTypeColumns,
+		name, t.Name, t.AllowsNegative, t.Decimals, t.DefaultCreditLimit, t.MonthlyWithdrawalCap, t.EarnsInterest, t.HoldsAssets, t.AllowsGoals)
+	t, err = scanWalletType(row)
+	if err = typeError(err); err != nil {
+		return wallet.Type{}, err
+	}
+
+	slog.InfoContext(ctx, "wallet type updated", "wallet_type", name, "new_name", t.Name)
+	return t, nil
+}
+
+// RetireWalletType stops new wallets from using a type. Existing wallets
+// keep it. Retiring a retired type is a no-op.
+func (p *Postgres) RetireWalletType(ctx context.Context, name string) (_ wallet.Type, err error) {
+	defer p.observe("RetireWalletType", time.Now(), &err)
+
+	row := p.db().QueryRowContext(ctx, "UPDATE wallet_types SET retired_at = COALESCE(retired_at, CURRENT_TIMESTAMP) WHERE name = $1 RETURNING "+walletTypeColumns, name)
+	t, err := scanWalletType(row)
+	if err != nil {
+		return wallet.Type{}, err
+	}
+
+	slog.InfoContext(ctx, "wallet type retired", "wallet_type", name)
+	return t, nil
+}
+
+// typeError maps a unique violation on the wallet type name to
+// wallet.ErrTypeExists.
+func typeError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return wallet.ErrTypeExists
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps buckets in process memory.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*Bucket
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*Bucket)}
+}
+
+func (m *Memory) TakeToken(ctx context.Context, key string, rule Rule, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &Bucket{Tokens: float64(rule.Burst), UpdatedAt: now}
+		m.buckets[key] = b
+	}
+	return b.Take(rule, now), nil
+}
+
+func (m *Memory) PruneBuckets(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, b := range m.buckets {
+		if b.UpdatedAt.Before(before) {
+			delete(m.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/logging"
+)
+
+// Rule is a token bucket: Burst tokens at most, refilled at Rate per second.
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available; zero if allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Bucket is the stored state of a single token bucket.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills b for the time elapsed since it was last updated and then
+// tries to consume one token.
+func (b *Bucket) Take(rule Rule, now time.Time) Result {
+	burst := float64(rule.Burst)
+	if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*rule.Rate)
+	}
+	b.UpdatedAt = now
+
+	res := Result{Limit: rule.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.Tokens) / rule.Rate)
+	}
+	res.Remaining = int(b.Tokens)
+	res.Reset = seconds((burst - b.Tokens) / rule.Rate)
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Backend stores token buckets. Memory suits a single instance; the
+// postgres store shares buckets between instances.
+type Backend interface {
+	TakeToken(ctx context.Context, key string, rule Rule, now time.Time) (Result, error)
+	// PruneBuckets deletes buckets not used since before.
+	PruneBuckets(ctx context.Context, before time.Time) error
+}
+
+// Limiter picks the rule for each request and applies it.
+type Limiter struct {
+	backend  Backend
+	def      Rule
+	mutating Rule
+	routes   map[string]Rule
+	apiKeys  map[string]bool
+	now      func() time.Time
+}
+
+// New returns a limiter applying cfg. Callers presenting one of apiKeys get
+// a bucket of their own; everyone else is limited by IP address.
+func New(backend Backend, cfg config.RateLimit, apiKeys []string) *Limiter {
+	l := &Limiter{
+		backend:  backend,
+		def:      Rule{Rate: cfg.DefaultRate, Burst: cfg.DefaultBurst},
+		mutating: Rule{Rate: cfg.MutatingRate, Burst: cfg.MutatingBurst},
+		routes:   make(map[string]Rule, len(cfg.Routes)),
+		apiKeys:  make(map[string]bool, len(apiKeys)),
+		now:      time.Now,
+	}
+	for route, r := range cfg.Routes {
+		l.routes[normalizeRoute(route)] = Rule{Rate: r.Rate, Burst: r.Burst}
+	}
+	for _, k := range apiKeys {
+		l.apiKeys[k] = true
+	}
+	return l
+}
+
+// rule returns the rule for a request and the scope its bucket is kept
+// under, so that e.g. writes do not consume the read allowance.
+func (l *Limiter) rule(method, route string) (string, Rule) {
+	if r, ok := l.routes[method+" "+route]; ok {
+		return method + " " + route, r
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return "mutating", l.mutating
+	}
+	return "default", l.def
+}
+
+// maxClientID bounds the client part of a bucket key, so that keys always
+// fit the postgres backend's column whatever the request headers hold.
+const maxClientID = 64
+
+// clientKey identifies the caller by a valid API key, otherwise by IP
+// address. Headers a client can set freely, such as an unknown API key,
+// must not pick the bucket, or every request could claim a fresh one. Keys
+// are stored as digests so that the backend never holds them in clear.
+func (l *Limiter) clientKey(c echo.Context) string {
+	if k := c.Request().Header.Get("X-API-Key"); k != "" && l.apiKeys[k] {
+		return "key:" + digest(k)
+	}
+	ip := c.RealIP()
+	if len(ip) > maxClientID {
+		ip = digest(ip)
+	}
+	return "ip:" + ip
+}
+
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Middleware rejects requests over their limit with 429 and reports the
+// caller's quota in RateLimit-* headers. If the backend fails the request
+// is let through: an unavailable limiter should not take the API down.
+func (l *Limiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			scope, rule := l.rule(c.Request().Method, c.Path())
+			res, err := l.backend.TakeToken(ctx, l.clientKey(c)+"|"+scope, rule, l.now())
+			if err != nil {
+				slog.ErrorContext(ctx, "rate limit backend failed", "error", err)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			if !res.Allowed {
+				h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+				return c.JSON(http.StatusTooManyRequests, Err{
+					Message:   "rate limit exceeded",
+					RequestID: logging.RequestID(ctx),
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+type Err struct {
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// normalizeRoute turns "post /api/v1/wallets" into "POST /api/v1/wallets".
+func normalizeRoute(route string) string {
+	method, path, _ := strings.Cut(strings.TrimSpace(route), " ")
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+}
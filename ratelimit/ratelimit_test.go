@@ -0,0 +1,154 @@
+//go:build unit
+
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/config"
+	"github.com/openmymai/fun-exercise-api/security"
+)
+
+func TestBucket(t *testing.T) {
+	t.Run("given empty bucket should refill at rate", func(t *testing.T) {
+		now := time.Now()
+		rule := Rule{Rate: 2, Burst: 2}
+		b := Bucket{Tokens: 2, UpdatedAt: now}
+
+		b.Take(rule, now)
+		b.Take(rule, now)
+		denied := b.Take(rule, now)
+		allowed := b.Take(rule, now.Add(500*time.Millisecond))
+
+		if denied.Allowed || denied.RetryAfter != 500*time.Millisecond {
+			t.Errorf("expected denial with 500ms retry but got %+v", denied)
+		}
+		if !allowed.Allowed || allowed.Remaining != 0 {
+			t.Errorf("expected refilled token to be allowed but got %+v", allowed)
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	newServer := func() *echo.Echo {
+		cfg := config.RateLimit{
+			DefaultRate: 10, DefaultBurst: 3,
+			MutatingRate: 1, MutatingBurst: 1,
+			Routes: map[string]config.RateLimitRule{"post /api/v1/transfers": {Rate: 1, Burst: 2}},
+		}
+		l := New(NewMemory(), cfg, []string{"k1", "k2"})
+		now := time.Now()
+		l.now = func() time.Time { return now }
+
+		e := echo.New()
+		e.IPExtractor = security.IPExtractor(config.Security{})
+		e.Use(l.Middleware())
+		ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+		e.GET("/api/v1/wallets", ok)
+		e.POST("/api/v1/wallets", ok)
+		e.POST("/api/v1/transfers", ok)
+		return e
+	}
+	do := func(e *echo.Echo, method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("given mutating requests over limit should return 429 with Retry-After", func(t *testing.T) {
+		e := newServer()
+
+		first := do(e, http.MethodPost, "/api/v1/wallets", "k1")
+		second := do(e, http.MethodPost, "/api/v1/wallets", "k1")
+
+		if first.Code != http.StatusOK || first.Header().Get("RateLimit-Limit") != "1" {
+			t.Errorf("expected first request allowed with limit 1 but got %d %v", first.Code, first.Header())
+		}
+		if second.Code != http.StatusTooManyRequests || second.Header().Get("Retry-After") != "1" {
+			t.Errorf("expected 429 with Retry-After 1 but got %d %v", second.Code, second.Header())
+		}
+	})
+
+	t.Run("given reads after writes are exhausted should still allow reads", func(t *testing.T) {
+		e := newServer()
+
+		do(e, http.MethodPost, "/api/v1/wallets", "k1")
+		rec := do(e, http.MethodGet, "/api/v1/wallets", "k1")
+
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != "2" {
+			t.Errorf("expected read allowed from its own bucket but got %d %v", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("given route override should use its limit", func(t *testing.T) {
+		e := newServer()
+
+		do(e, http.MethodPost, "/api/v1/transfers", "k1")
+		rec := do(e, http.MethodPost, "/api/v1/transfers", "k1")
+
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "2" {
+			t.Errorf("expected route limit of 2 but got %d %v", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("given different clients should keep separate buckets", func(t *testing.T) {
+		e := newServer()
+
+		do(e, http.MethodPost, "/api/v1/wallets", "k1")
+		rec := do(e, http.MethodPost, "/api/v1/wallets", "k2")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected other client allowed but got %d", rec.Code)
+		}
+	})
+
+	t.Run("given unknown API keys should share the caller's IP bucket", func(t *testing.T) {
+		e := newServer()
+
+		do(e, http.MethodPost, "/api/v1/wallets", "forged-1")
+		rec := do(e, http.MethodPost, "/api/v1/wallets", "forged-2")
+
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected 429 for a fresh unknown key but got %d", rec.Code)
+		}
+	})
+
+	t.Run("given changing X-Forwarded-For should keep the caller's IP bucket", func(t *testing.T) {
+		e := newServer()
+		forwarded := func(ip string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/wallets", nil)
+			req.Header.Set(echo.HeaderXForwardedFor, ip)
+			req.Header.Set(echo.HeaderXRealIP, ip)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec
+		}
+
+		forwarded("198.51.100.1")
+		rec := forwarded("198.51.100.2")
+
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected 429 despite a new forwarded address but got %d", rec.Code)
+		}
+	})
+
+	t.Run("given oversized client address should keep the key bounded", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXForwardedFor, strings.Repeat("1", 4096))
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		l := New(NewMemory(), config.RateLimit{}, nil)
+
+		if key := l.clientKey(c); len(key) > len("ip:")+maxClientID {
+			t.Errorf("expected key of at most %d bytes but got %d", len("ip:")+maxClientID, len(key))
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package security
+
+import (
+	"net"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+// apiCSP locks JSON responses down completely; they never load resources.
+const apiCSP = "default-src 'none'; frame-ancestors 'none'"
+
+// SwaggerCSP allows what the swagger UI bundle needs: its own scripts and
+// styles, which it injects inline, and data: images for icons.
+const SwaggerCSP = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+// Headers sets the standard security headers on every response. HSTS is
+// only sent on TLS requests, as browsers ignore it otherwise.
+func Headers(cfg config.Security) echo.MiddlewareFunc {
+	csp := cfg.ContentSecurityPolicy
+	if csp == "" {
+		csp = apiCSP
+	}
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSExcludeSubdomains: !cfg.HSTSIncludeSubdomains,
+		ContentSecurityPolicy: csp,
+		ReferrerPolicy:        "no-referrer",
+	})
+}
+
+// ContentSecurityPolicy overrides the policy set by Headers for a group of
+// routes, e.g. the swagger UI.
+func ContentSecurityPolicy(policy string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderContentSecurityPolicy, policy)
+			return next(c)
+		}
+	}
+}
+
+// IPExtractor returns how the client's address is found. Forwarding
+// headers are only believed when the request came through one of the
+// trusted proxies; otherwise any client could pick its own address.
+func IPExtractor(cfg config.Security) echo.IPExtractor {
+	if len(cfg.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range cfg.TrustedProxies {
+		if _, n, err := net.ParseCIDR(cidr); err == nil {
+			opts = append(opts, echo.TrustIPRange(n))
+		}
+	}
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
+
+// CORS allows the configured browser origins to call the API.
+func CORS(cfg config.CORS) echo.MiddlewareFunc {
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+}
@@ -0,0 +1,169 @@
+//go:build unit
+
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+func writeCert(t *testing.T, dir, cn string, modTime time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	os.Chtimes(certFile, modTime, modTime)
+	os.Chtimes(keyFile, modTime, modTime)
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, r *CertReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	t.Run("given renewed certificate on disk should serve it after reload", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Now()
+		certFile, keyFile := writeCert(t, dir, "old", now.Add(-time.Minute))
+		r, err := NewCertReloader(certFile, keyFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		writeCert(t, dir, "new", now)
+		if err := r.Reload(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := commonName(t, r); got != "new" {
+			t.Errorf("expected renewed certificate but got %q", got)
+		}
+	})
+
+	t.Run("given broken certificate on disk should keep serving the old one", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeCert(t, dir, "old", time.Now().Add(-time.Minute))
+		r, err := NewCertReloader(certFile, keyFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		os.WriteFile(certFile, []byte("garbage"), 0o600)
+		if err := r.Reload(); err == nil {
+			t.Error("expected reload error")
+		}
+
+		if got := commonName(t, r); got != "old" {
+			t.Errorf("expected previous certificate but got %q", got)
+		}
+	})
+}
+
+func TestHeaders(t *testing.T) {
+	t.Run("given cross-origin preflight from allowed origin should allow it", func(t *testing.T) {
+		e := echo.New()
+		e.Use(Headers(config.Security{HSTSMaxAge: 60}))
+		e.Use(CORS(config.CORS{AllowOrigins: []string{"https://app.example.com"}}))
+		e.GET("/api/v1/wallets", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/wallets", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "https://app.example.com" {
+			t.Errorf("expected origin to be allowed but got %q", got)
+		}
+		if got := rec.Header().Get(echo.HeaderXContentTypeOptions); got != "nosniff" {
+			t.Errorf("expected nosniff but got %q", got)
+		}
+		if got := rec.Header().Get(echo.HeaderStrictTransportSecurity); got != "" {
+			t.Errorf("expected no HSTS over plain http but got %q", got)
+		}
+	})
+
+	t.Run("given swagger route should use its own content security policy", func(t *testing.T) {
+		e := echo.New()
+		e.Use(Headers(config.Security{}))
+		e.GET("/swagger/*", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, ContentSecurityPolicy(SwaggerCSP))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+
+		if got := rec.Header().Get(echo.HeaderContentSecurityPolicy); got != SwaggerCSP {
+			t.Errorf("expected swagger policy but got %q", got)
+		}
+	})
+}
+
+func TestIPExtractor(t *testing.T) {
+	request := func(remote, xff string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remote
+		req.Header.Set(echo.HeaderXForwardedFor, xff)
+		return req
+	}
+
+	t.Run("given no trusted proxies should ignore X-Forwarded-For", func(t *testing.T) {
+		extract := IPExtractor(config.Security{})
+
+		if got := extract(request("203.0.113.7:4000", "198.51.100.1")); got != "203.0.113.7" {
+			t.Errorf("expected peer address but got %q", got)
+		}
+	})
+
+	t.Run("given trusted proxy should take the client from X-Forwarded-For", func(t *testing.T) {
+		extract := IPExtractor(config.Security{TrustedProxies: []string{"10.0.0.0/8"}})
+
+		if got := extract(request("10.1.2.3:4000", "198.51.100.1")); got != "198.51.100.1" {
+			t.Errorf("expected forwarded client but got %q", got)
+		}
+		if got := extract(request("203.0.113.7:4000", "198.51.100.1")); got != "203.0.113.7" {
+			t.Errorf("expected peer address for untrusted peer but got %q", got)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+//go:build unit
+
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("given incoming traceparent should continue the trace", func(t *testing.T) {
+		rec := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		e := echo.New()
+		e.Use(Middleware())
+		var handlerSpan trace.SpanContext
+		e.GET("/api/v1/wallets", func(c echo.Context) error {
+			ctx, span := Start(c.Request().Context(), "handler")
+			defer span.End()
+			handlerSpan = trace.SpanContextFromContext(ctx)
+			return c.NoContent(http.StatusInternalServerError)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/wallets", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := rec.Ended()
+		if len(spans) != 2 {
+			t.Fatalf("expected 2 spans but got %d", len(spans))
+		}
+		server := spans[1]
+		if got := server.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("expected incoming trace id but got %s", got)
+		}
+		if server.Name() != "GET /api/v1/wallets" {
+			t.Errorf("expected span named by route but got %q", server.Name())
+		}
+		if handlerSpan.TraceID() != server.SpanContext().TraceID() {
+			t.Error("expected handler span in the same trace")
+		}
+		if server.Status().Code.String() != "Error" {
+			t.Errorf("expected error status for 500 but got %v", server.Status())
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+)
+
+// maxSeriesPoints bounds the length of a balance series.
+const maxSeriesPoints = 1000
+
+var (
+	ErrInvalidAsOf        = errors.New("as_of must be a date (YYYY-MM-DD) or an RFC 3339 time")
+	ErrInvalidGranularity = errors.New("granularity must be daily, weekly or monthly")
+	ErrSeriesTooLong      = errors.New("series would have more than 1000 points")
+)
+
+// Balance is a wallet's balance at a point in time, derived from its
+// ledger.
+type Balance struct {
+	WalletID int       `json:"wallet_id" example:"1"`
+	AsOf     time.Time `json:"as_of" example:"2024-04-01T00:00:00Z"`
+	Balance  float64   `json:"balance" example:"1250.00"`
+	Currency string    `json:"currency" example:"USD"`
+}
+
+// BalancePoint is a wallet's balance at the end of Date.
+type BalancePoint struct {
+	Date    time.Time `json:"date" example:"2024-03-31T00:00:00Z"`
+	Balance float64   `json:"balance" example:"1250.00"`
+}
+
+// BalanceSeries is a wallet's closing balance at the end of each daily,
+// weekly or monthly period between From and To.
+type BalanceSeries struct {
+	WalletID    int            `json:"wallet_id" example:"1"`
+	Currency    string         `json:"currency" example:"USD"`
+	Granularity string         `json:"granularity" example:"monthly"`
+	From        time.Time      `json:"from" example:"2024-01-01T00:00:00Z"`
+	To          time.Time      `json:"to" example:"2024-03-31T00:00:00Z"`
+	Points      []BalancePoint `json:"points"`
+}
+
+// ParseAsOf parses the as_of query parameter. A date means the end of that
+// day, UTC, and an empty value means now. The result is exclusive: ledger
+// entries made before it count.
+func ParseAsOf(asOf string, now time.Time) (time.Time, error) {
+	if asOf == "" {
+		return now.UTC(), nil
+	}
+	if d, err := time.Parse(time.DateOnly, asOf); err == nil {
+		return d.AddDate(0, 0, 1), nil
+	}
+	t, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return time.Time{}, ErrInvalidAsOf
+	}
+	return t.UTC(), nil
+}
+
+// SeriesDates returns the last day of each period of granularity from from
+// to to, both dates. The last period is cut short at to.
+func SeriesDates(granularity string, from, to time.Time) ([]time.Time, error) {
+	if granularity != PeriodDaily && granularity != PeriodWeekly && granularity != PeriodMonthly {
+		return nil, ErrInvalidGranularity
+	}
+	var dates []time.Time
+	for d := from; !d.After(to); {
+		end := PeriodEnd(granularity, PeriodStart(granularity, d)).AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		if len(dates) == maxSeriesPoints {
+			return nil, ErrSeriesTooLong
+		}
+		dates = append(dates, end)
+		d = end.AddDate(0, 0, 1)
+	}
+	return dates, nil
+}
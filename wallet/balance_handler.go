@@ -0,0 +1,71 @@
+package wallet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// BalanceHandler
+//
+//	@Summary		Get balance as of a time
+//	@Description	Get a wallet's balance at a point in the past, derived from its ledger. A date means the end of that day, UTC; without as_of the current balance is returned.
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id		path		int		true	"Wallet ID"
+//	@Param			as_of	query		string	false	"Date (YYYY-MM-DD) or RFC 3339 time"
+//	@Success		200		{object}	Balance
+//	@Router			/api/v1/wallets/{id}/balance [get]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) BalanceHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.BalanceHandler")
+	defer span.End()
+
+	asOf, err := ParseAsOf(c.QueryParam("as_of"), time.Now())
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	balance, err := h.store.BalanceAt(ctx, c.Param("id"), asOf)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, balance)
+}
+
+// BalanceSeriesHandler
+//
+//	@Summary		Get balance history
+//	@Description	Get a wallet's closing balance at the end of each day, week (ending Sunday) or month between two dates, inclusive. The range defaults to the current month and the granularity to daily.
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id			path		int		true	"Wallet ID"
+//	@Param			from		query		string	false	"First day (YYYY-MM-DD)"
+//	@Param			to			query		string	false	"Last day (YYYY-MM-DD)"
+//	@Param			granularity	query		string	false	"daily, weekly or monthly"
+//	@Success		200			{object}	BalanceSeries
+//	@Router			/api/v1/wallets/{id}/balance/series [get]
+//	@Failure		400			{object}	Err
+//	@Failure		404			{object}	Err
+//	@Failure		500			{object}	Err
+func (h *Handler) BalanceSeriesHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.BalanceSeriesHandler")
+	defer span.End()
+
+	from, to, err := ParseDateRange(c.QueryParam("from"), c.QueryParam("to"), time.Now())
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	granularity := c.QueryParam("granularity")
+	if granularity == "" {
+		granularity = PeriodDaily
+	}
+	series, err := h.store.BalanceSeries(ctx, c.Param("id"), granularity, from, to)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, series)
+}
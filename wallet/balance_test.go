@@ -0,0 +1,66 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseAsOf(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		asOf string
+		want time.Time
+		err  error
+	}{
+		{"", now, nil},
+		{"2024-03-31", date(2024, 4, 1), nil},
+		{"2024-03-31T12:30:00+07:00", time.Date(2024, 3, 31, 5, 30, 0, 0, time.UTC), nil},
+		{"yesterday", time.Time{}, ErrInvalidAsOf},
+	}
+	for _, tt := range tests {
+		got, err := ParseAsOf(tt.asOf, now)
+		if !errors.Is(err, tt.err) || !got.Equal(tt.want) {
+			t.Errorf("ParseAsOf(%q): expected %v, %v but got %v, %v", tt.asOf, tt.want, tt.err, got, err)
+		}
+	}
+}
+
+func TestSeriesDates(t *testing.T) {
+	tests := []struct {
+		granularity string
+		from, to    time.Time
+		want        []time.Time
+	}{
+		{PeriodDaily, date(2024, 2, 28), date(2024, 3, 1), []time.Time{date(2024, 2, 28), date(2024, 2, 29), date(2024, 3, 1)}},
+		// Weeks end on Sunday.
+		{PeriodWeekly, date(2024, 3, 6), date(2024, 3, 20), []time.Time{date(2024, 3, 10), date(2024, 3, 17), date(2024, 3, 20)}},
+		{PeriodMonthly, date(2024, 1, 15), date(2024, 3, 31), []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.granularity, func(t *testing.T) {
+			got, err := SeriesDates(tt.granularity, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := SeriesDates("hourly", date(2024, 1, 1), date(2024, 1, 2)); !errors.Is(err, ErrInvalidGranularity) {
+		t.Errorf("expected %v but got %v", ErrInvalidGranularity, err)
+	}
+	if _, err := SeriesDates(PeriodDaily, date(2020, 1, 1), date(2024, 1, 1)); !errors.Is(err, ErrSeriesTooLong) {
+		t.Errorf("expected %v but got %v", ErrSeriesTooLong, err)
+	}
+}
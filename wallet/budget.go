@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Budget periods. Periods start at midnight UTC; weeks start on Monday.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// BudgetThresholds are the percentages of a budget at which an alert is
+// raised, at most once per budget and period.
+var BudgetThresholds = []int{80, 100}
+
+// SpendingKinds are the ledger kinds whose debits count against a budget.
+// Transfers and holds are not spending: a transfer moves money between
+// wallets and a hold only counts once it is captured.
+var SpendingKinds = []string{KindWithdrawal, KindCapture, KindFee}
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+	ErrInvalidBudget  = errors.New("budget needs a positive amount, a daily, weekly or monthly period and exactly one of wallet_id or user_id")
+)
+
+// Budget caps spending per period, either on one wallet or across all of a
+// user's wallets. A budget with a category only counts debits in it.
+type Budget struct {
+	ID        int64     `json:"id" example:"1"`
+	WalletID  *int      `json:"wallet_id,omitempty" example:"1"`
+	UserID    *int      `json:"user_id,omitempty"`
+	Category  string    `json:"category,omitempty" example:"groceries"`
+	Amount    float64   `json:"amount" example:"500.00"`
+	Period    string    `json:"period" example:"monthly"`
+	CreatedAt time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// Validate reports whether b can be created.
+func (b Budget) Validate() error {
+	switch {
+	case b.Amount <= 0, (b.WalletID == nil) == (b.UserID == nil):
+		return ErrInvalidBudget
+	case b.Period != PeriodDaily && b.Period != PeriodWeekly && b.Period != PeriodMonthly:
+		return ErrInvalidBudget
+	}
+	return nil
+}
+
+// BudgetStatus is a budget with its consumption in the current period.
+type BudgetStatus struct {
+	Budget
+	PeriodStart time.Time `json:"period_start" example:"2024-03-01T00:00:00Z"`
+	PeriodEnd   time.Time `json:"period_end" example:"2024-04-01T00:00:00Z"`
+	Spent       float64   `json:"spent" example:"420.00"`
+	// Remaining is negative once the budget is overspent.
+	Remaining float64 `json:"remaining" example:"80.00"`
+	// Percent is the share of the budget spent, rounded to one decimal.
+	Percent float64 `json:"percent" example:"84.0"`
+}
+
+// NewBudgetStatus returns the status of b given what was spent in the
+// period starting at start.
+func NewBudgetStatus(b Budget, start time.Time, spent float64) BudgetStatus {
+	return BudgetStatus{
+		Budget:      b,
+		PeriodStart: start,
+		PeriodEnd:   PeriodEnd(b.Period, start),
+		Spent:       spent,
+		Remaining:   Round(b.Amount-spent, 2),
+		Percent:     math.Round(spent/b.Amount*1000) / 10,
+	}
+}
+
+// BudgetAlert records that spending in a period reached a threshold.
+type BudgetAlert struct {
+	BudgetID    int64     `json:"budget_id" example:"1"`
+	PeriodStart time.Time `json:"period_start" example:"2024-03-01T00:00:00Z"`
+	Threshold   int       `json:"threshold" example:"80"`
+	Spent       float64   `json:"spent" example:"420.00"`
+	// TransactionID is the debit that crossed the threshold.
+	TransactionID int64     `json:"transaction_id" example:"12"`
+	CreatedAt     time.Time `json:"created_at" example:"2024-03-20T09:30:00Z"`
+}
+
+// PeriodStart returns the start of the budget period containing now.
+func PeriodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PeriodMonthly:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// PeriodEnd returns the end of the budget period starting at start.
+func PeriodEnd(period string, start time.Time) time.Time {
+	switch period {
+	case PeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonthly:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// CrossedThresholds returns the thresholds of a budget of amount that
+// spending moving from before to after reached.
+func CrossedThresholds(amount, before, after float64) []int {
+	var crossed []int
+	for _, t := range BudgetThresholds {
+		limit := amount * float64(t) / 100
+		if before < limit && after >= limit {
+			crossed = append(crossed, t)
+		}
+	}
+	return crossed
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// CreateBudgetHandler
+//
+//	@Summary		Create budget
+//	@Description	Cap spending per day, week or month on a wallet (wallet_id) or across a user's wallets (user_id), optionally in one category. Withdrawals, captured holds and fees count as spending; an alert is recorded when spending reaches 80% and 100% of the budget.
+//	@Tags			budget
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		Budget	true	"Budget"
+//	@Success		201		{object}	Budget
+//	@Router			/api/v1/budgets [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateBudgetHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateBudgetHandler")
+	defer span.End()
+
+	b := Budget{}
+	if err := c.Bind(&b); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := b.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	b, err := h.store.CreateBudget(ctx, b)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, b)
+}
+
+// BudgetHandler
+//
+//	@Summary		Get budget
+//	@Description	Get a budget with what was spent and what remains in the current period
+//	@Tags			budget
+//	@Produce		json
+//	@Param			id	path		int	true	"Budget ID"
+//	@Success		200	{object}	BudgetStatus
+//	@Router			/api/v1/budgets/{id} [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) BudgetHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.BudgetHandler")
+	defer span.End()
+
+	b, err := h.store.Budget(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, b)
+}
+
+// DeleteBudgetHandler
+//
+//	@Summary		Delete budget
+//	@Description	Delete a budget and its alerts
+//	@Tags			budget
+//	@Produce		json
+//	@Param			id	path		int	true	"Budget ID"
+//	@Success		200	{object}	Budget
+//	@Router			/api/v1/budgets/{id} [delete]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) DeleteBudgetHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DeleteBudgetHandler")
+	defer span.End()
+
+	b, err := h.store.DeleteBudget(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, b)
+}
+
+// BudgetAlertsHandler
+//
+//	@Summary		List budget alerts
+//	@Description	List the thresholds a budget has reached, newest period first
+//	@Tags			budget
+//	@Produce		json
+//	@Param			id	path		int	true	"Budget ID"
+//	@Success		200	{array}		BudgetAlert
+//	@Router			/api/v1/budgets/{id}/alerts [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) BudgetAlertsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.BudgetAlertsHandler")
+	defer span.End()
+
+	alerts, err := h.store.BudgetAlerts(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, alerts)
+}
+
+// WalletBudgetsHandler
+//
+//	@Summary		List wallet budgets
+//	@Description	List the budgets set on a wallet with their remaining amount in the current period
+//	@Tags			budget
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{array}		BudgetStatus
+//	@Router			/api/v1/wallets/{id}/budgets [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) WalletBudgetsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletBudgetsHandler")
+	defer span.End()
+
+	budgets, err := h.store.WalletBudgets(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, budgets)
+}
+
+// UserBudgetsHandler
+//
+//	@Summary		List user budgets
+//	@Description	List a user's budgets, across their wallets and on single wallets, with their remaining amount in the current period
+//	@Tags			budget
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{array}		BudgetStatus
+//	@Router			/api/v1/users/{id}/budgets [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) UserBudgetsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UserBudgetsHandler")
+	defer span.End()
+
+	budgets, err := h.store.UserBudgets(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, budgets)
+}
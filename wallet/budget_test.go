@@ -0,0 +1,82 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// A Sunday evening, which belongs to the week starting on Monday the 18th.
+	now := time.Date(2024, 3, 24, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period string
+		start  time.Time
+		end    time.Time
+	}{
+		{PeriodDaily, time.Date(2024, 3, 24, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{PeriodWeekly, time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{PeriodMonthly, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			start := PeriodStart(tt.period, now)
+			if !start.Equal(tt.start) {
+				t.Errorf("expected start %v but got %v", tt.start, start)
+			}
+			if end := PeriodEnd(tt.period, start); !end.Equal(tt.end) {
+				t.Errorf("expected end %v but got %v", tt.end, end)
+			}
+		})
+	}
+}
+
+func TestCrossedThresholds(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after float64
+		want          []int
+	}{
+		{"below both", 100, 300, nil},
+		{"reaches 80%", 300, 400, []int{80}},
+		{"already past 80%", 400, 450, nil},
+		{"reaches 100% exactly", 450, 500, []int{100}},
+		{"jumps past both", 0, 600, []int{80, 100}},
+		{"already overspent", 600, 700, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CrossedThresholds(500, tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBudgetValidate(t *testing.T) {
+	id := 1
+	tests := []struct {
+		name   string
+		budget Budget
+		want   error
+	}{
+		{"wallet budget", Budget{WalletID: &id, Amount: 500, Period: PeriodMonthly}, nil},
+		{"user budget in a category", Budget{UserID: &id, Category: "groceries", Amount: 100, Period: PeriodWeekly}, nil},
+		{"no owner", Budget{Amount: 500, Period: PeriodMonthly}, ErrInvalidBudget},
+		{"both owners", Budget{WalletID: &id, UserID: &id, Amount: 500, Period: PeriodMonthly}, ErrInvalidBudget},
+		{"zero amount", Budget{WalletID: &id, Period: PeriodDaily}, ErrInvalidBudget},
+		{"unknown period", Budget{WalletID: &id, Amount: 500, Period: "yearly"}, ErrInvalidBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.budget.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, err)
+			}
+		})
+	}
+}
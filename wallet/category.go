@@ -0,0 +1,183 @@
+package wallet
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Uncategorized labels spending without a category in reports.
+const Uncategorized = "uncategorized"
+
+// Fields a category rule can match on.
+const (
+	RuleMerchant    = "merchant"
+	RuleDescription = "description"
+)
+
+const (
+	maxTags      = 10
+	maxTagLength = 32
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryInUse    = errors.New("category is used by transactions or budgets")
+	ErrInvalidCategory  = errors.New("category name must be 1 to 64 lower-case characters")
+	// ErrUnknownCategory is returned when a transaction or budget names a
+	// category that does not exist.
+	ErrUnknownCategory  = errors.New("unknown category")
+	ErrRuleNotFound     = errors.New("category rule not found")
+	ErrInvalidRule      = errors.New("rule needs a category, a field of merchant or description and a valid regular expression")
+	ErrInvalidTags      = errors.New("at most 10 tags of up to 32 characters each")
+	ErrInvalidDateRange = errors.New("from and to must be dates (YYYY-MM-DD) with from not after to")
+)
+
+// Category classifies ledger entries for budgets and spending reports.
+type Category struct {
+	Name        string    `json:"name" example:"groceries"`
+	Description string    `json:"description,omitempty" example:"Supermarkets and food shops"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// Validate normalises c's name to lower case and reports whether it is
+// usable.
+func (c *Category) Validate() error {
+	c.Name = strings.ToLower(strings.TrimSpace(c.Name))
+	if c.Name == "" || len(c.Name) > 64 || c.Name == Uncategorized {
+		return ErrInvalidCategory
+	}
+	return nil
+}
+
+// CategoryRule assigns Category to new entries without one whose merchant
+// or description matches Pattern, a case-insensitive regular expression.
+// Rules are tried by ascending priority, then age; the first match wins.
+type CategoryRule struct {
+	ID        int64     `json:"id" example:"1"`
+	Category  string    `json:"category" example:"groceries"`
+	Field     string    `json:"field" example:"merchant"`
+	Pattern   string    `json:"pattern" example:"tesco|lidl|aldi"`
+	Priority  int       `json:"priority" example:"10"`
+	CreatedAt time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// Validate reports whether r can be saved.
+func (r *CategoryRule) Validate() error {
+	r.Category = strings.ToLower(strings.TrimSpace(r.Category))
+	if r.Category == "" || (r.Field != RuleMerchant && r.Field != RuleDescription) || r.Pattern == "" {
+		return ErrInvalidRule
+	}
+	if _, err := r.compile(); err != nil {
+		return errors.Join(ErrInvalidRule, err)
+	}
+	return nil
+}
+
+func (r CategoryRule) compile() (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + r.Pattern)
+}
+
+// Categorize returns the category of the first of rules, which must be
+// ordered, that matches merchant or description, or "" if none does.
+// Rules that no longer compile are skipped.
+func Categorize(rules []CategoryRule, merchant, description string) string {
+	for _, r := range rules {
+		value := merchant
+		if r.Field == RuleDescription {
+			value = description
+		}
+		if value == "" {
+			continue
+		}
+		re, err := r.compile()
+		if err == nil && re.MatchString(value) {
+			return r.Category
+		}
+	}
+	return ""
+}
+
+// NormalizeTags lower-cases, trims and de-duplicates tags, keeping their
+// order.
+func NormalizeTags(tags []string) ([]string, error) {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(out, tag) {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, ErrInvalidTags
+		}
+		out = append(out, tag)
+	}
+	if len(out) > maxTags {
+		return nil, ErrInvalidTags
+	}
+	return out, nil
+}
+
+// CategorySpending is the spending in one category of a report.
+type CategorySpending struct {
+	Category string  `json:"category" example:"groceries"`
+	Amount   float64 `json:"amount" example:"420.00"`
+	Count    int     `json:"count" example:"12"`
+	// Percent is the category's share of the report total.
+	Percent float64 `json:"percent" example:"35.0"`
+}
+
+// SpendingReport totals spending debits by category between From and To,
+// both inclusive.
+type SpendingReport struct {
+	From       time.Time          `json:"from" example:"2024-03-01T00:00:00Z"`
+	To         time.Time          `json:"to" example:"2024-03-31T00:00:00Z"`
+	Total      float64            `json:"total" example:"1200.00"`
+	Categories []CategorySpending `json:"categories"`
+}
+
+// NewSpendingReport totals categories, which are ordered by amount, into a
+// report.
+func NewSpendingReport(from, to time.Time, categories []CategorySpending) SpendingReport {
+	r := SpendingReport{From: from, To: to, Categories: categories}
+	for _, c := range categories {
+		r.Total += c.Amount
+	}
+	r.Total = Round(r.Total, 2)
+	for i := range r.Categories {
+		if r.Total > 0 {
+			r.Categories[i].Percent = Round(r.Categories[i].Amount/r.Total*100, 1)
+		}
+	}
+	if r.Categories == nil {
+		r.Categories = []CategorySpending{}
+	}
+	return r
+}
+
+// ParseDateRange parses the from and to query parameters of a report. An
+// empty from defaults to the first day of the month of now and an empty to
+// to the day of now.
+func ParseDateRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var err error
+	if from != "" {
+		if start, err = time.Parse(time.DateOnly, from); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+	}
+	if to != "" {
+		if end, err = time.Parse(time.DateOnly, to); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return start, end, nil
+}
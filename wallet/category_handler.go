@@ -0,0 +1,243 @@
+package wallet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// CategoriesHandler
+//
+//	@Summary		List categories
+//	@Description	List the categories ledger entries and budgets can use
+//	@Tags			category
+//	@Produce		json
+//	@Success		200	{array}		Category
+//	@Router			/api/v1/categories [get]
+//	@Failure		500	{object}	Err
+func (h *Handler) CategoriesHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CategoriesHandler")
+	defer span.End()
+
+	categories, err := h.store.Categories(ctx)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategoryHandler
+//
+//	@Summary		Create category
+//	@Description	Create a category. Names are stored in lower case.
+//	@Tags			category
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		Category	true	"Category"
+//	@Success		201		{object}	Category
+//	@Router			/api/v1/admin/categories [post]
+//	@Failure		400		{object}	Err
+//	@Failure		409		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateCategoryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateCategoryHandler")
+	defer span.End()
+
+	category := Category{}
+	if err := c.Bind(&category); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	category, err := h.store.CreateCategory(ctx, category)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategoryHandler
+//
+//	@Summary		Update category
+//	@Description	Rename a category or change its description. Ledger entries, budgets and rules follow a rename.
+//	@Tags			category
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string		true	"Category name"
+//	@Param			body	body		Category	true	"Category"
+//	@Success		200		{object}	Category
+//	@Router			/api/v1/admin/categories/{name} [put]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		409		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) UpdateCategoryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UpdateCategoryHandler")
+	defer span.End()
+
+	category := Category{}
+	if err := c.Bind(&category); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	category, err := h.store.UpdateCategory(ctx, c.Param("name"), category)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategoryHandler
+//
+//	@Summary		Delete category
+//	@Description	Delete a category and its rules. Categories still used by ledger entries or budgets cannot be deleted.
+//	@Tags			category
+//	@Produce		json
+//	@Param			name	path		string	true	"Category name"
+//	@Success		200		{object}	Category
+//	@Router			/api/v1/admin/categories/{name} [delete]
+//	@Failure		404		{object}	Err
+//	@Failure		409		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) DeleteCategoryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DeleteCategoryHandler")
+	defer span.End()
+
+	category, err := h.store.DeleteCategory(ctx, c.Param("name"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
+// CategoryRulesHandler
+//
+//	@Summary		List category rules
+//	@Description	List the auto-categorisation rules in the order they are tried
+//	@Tags			category
+//	@Produce		json
+//	@Success		200	{array}		CategoryRule
+//	@Router			/api/v1/admin/category-rules [get]
+//	@Failure		500	{object}	Err
+func (h *Handler) CategoryRulesHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CategoryRulesHandler")
+	defer span.End()
+
+	rules, err := h.store.CategoryRules(ctx)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, rules)
+}
+
+// CreateCategoryRuleHandler
+//
+//	@Summary		Create category rule
+//	@Description	Categorise new deposits and withdrawals without a category whose merchant or description matches a case-insensitive regular expression. Rules are tried by ascending priority; the first match wins.
+//	@Tags			category
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		CategoryRule	true	"Rule"
+//	@Success		201		{object}	CategoryRule
+//	@Router			/api/v1/admin/category-rules [post]
+//	@Failure		400		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateCategoryRuleHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateCategoryRuleHandler")
+	defer span.End()
+
+	rule := CategoryRule{}
+	if err := c.Bind(&rule); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := rule.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	rule, err := h.store.CreateCategoryRule(ctx, rule)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// DeleteCategoryRuleHandler
+//
+//	@Summary		Delete category rule
+//	@Description	Delete a category rule. Entries it already categorised keep their category.
+//	@Tags			category
+//	@Produce		json
+//	@Param			id	path		int	true	"Rule ID"
+//	@Success		200	{object}	CategoryRule
+//	@Router			/api/v1/admin/category-rules/{id} [delete]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) DeleteCategoryRuleHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DeleteCategoryRuleHandler")
+	defer span.End()
+
+	rule, err := h.store.DeleteCategoryRule(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, rule)
+}
+
+// WalletSpendingHandler
+//
+//	@Summary		Wallet spending by category
+//	@Description	Total a wallet's withdrawals, captured holds and fees by category between two dates, inclusive. The range defaults to the current month.
+//	@Tags			category
+//	@Produce		json
+//	@Param			id		path		int		true	"Wallet ID"
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD)"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD)"
+//	@Success		200		{object}	SpendingReport
+//	@Router			/api/v1/wallets/{id}/spending [get]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) WalletSpendingHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletSpendingHandler")
+	defer span.End()
+
+	from, to, err := ParseDateRange(c.QueryParam("from"), c.QueryParam("to"), time.Now())
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	report, err := h.store.WalletSpending(ctx, c.Param("id"), from, to)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// UserSpendingHandler
+//
+//	@Summary		User spending by category
+//	@Description	Total the withdrawals, captured holds and fees of all of a user's wallets by category between two dates, inclusive. The range defaults to the current month.
+//	@Tags			category
+//	@Produce		json
+//	@Param			id		path		int		true	"User ID"
+//	@Param			from	query		string	false	"First day (YYYY-MM-DD)"
+//	@Param			to		query		string	false	"Last day (YYYY-MM-DD)"
+//	@Success		200		{object}	SpendingReport
+//	@Router			/api/v1/users/{id}/spending [get]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) UserSpendingHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UserSpendingHandler")
+	defer span.End()
+
+	from, to, err := ParseDateRange(c.QueryParam("from"), c.QueryParam("to"), time.Now())
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	report, err := h.store.UserSpending(ctx, c.Param("id"), from, to)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCategorize(t *testing.T) {
+	rules := []CategoryRule{
+		{Category: "dining", Field: RuleMerchant, Pattern: `^tesco express$`},
+		{Category: "groceries", Field: RuleMerchant, Pattern: `tesco|lidl|aldi`},
+		{Category: "rent", Field: RuleDescription, Pattern: `\brent\b`},
+		{Category: "broken", Field: RuleMerchant, Pattern: `(`},
+	}
+
+	tests := []struct {
+		name        string
+		merchant    string
+		description string
+		want        string
+	}{
+		{"first matching rule wins", "Tesco Express", "", "dining"},
+		{"case-insensitive", "LIDL Berlin", "", "groceries"},
+		{"matches description", "", "March rent", "rent"},
+		{"field must match", "Rent-a-car", "", ""},
+		{"no match", "Cinema", "Tickets", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Categorize(rules, tt.merchant, tt.description); got != tt.want {
+				t.Errorf("expected %q but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCategoryRuleValidate(t *testing.T) {
+	valid := CategoryRule{Category: " Groceries ", Field: RuleMerchant, Pattern: "tesco"}
+	if err := valid.Validate(); err != nil || valid.Category != "groceries" {
+		t.Errorf("expected a valid rule for groceries but got %q, %v", valid.Category, err)
+	}
+
+	for _, r := range []CategoryRule{
+		{Category: "groceries", Field: "amount", Pattern: "tesco"},
+		{Category: "groceries", Field: RuleMerchant, Pattern: "("},
+		{Field: RuleMerchant, Pattern: "tesco"},
+	} {
+		if err := r.Validate(); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("expected %v for %+v but got %v", ErrInvalidRule, r, err)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{" Family", "family", "", "Holiday "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"family", "holiday"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+
+	if _, err := NormalizeTags([]string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("expected %v but got %v", ErrInvalidTags, err)
+	}
+}
+
+func TestSpendingReport(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	got := NewSpendingReport(from, to, []CategorySpending{
+		{Category: "rent", Amount: 900, Count: 1},
+		{Category: "groceries", Amount: 250, Count: 6},
+		{Category: Uncategorized, Amount: 50, Count: 2},
+	})
+
+	if got.Total != 1200 {
+		t.Errorf("expected total 1200 but got %v", got.Total)
+	}
+	want := []float64{75, 20.8, 4.2}
+	for i, c := range got.Categories {
+		if c.Percent != want[i] {
+			t.Errorf("expected %s to be %v%% but got %v%%", c.Category, want[i], c.Percent)
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2024, 3, 25, 14, 0, 0, 0, time.UTC)
+
+	from, to, err := ParseDateRange("", "", now)
+	if err != nil || !from.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the month to date but got %v to %v, %v", from, to, err)
+	}
+	if _, _, err := ParseDateRange("2024-03-10", "2024-03-01", now); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("expected %v but got %v", ErrInvalidDateRange, err)
+	}
+	if _, _, err := ParseDateRange("March", "", now); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("expected %v but got %v", ErrInvalidDateRange, err)
+	}
+}
@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// KindRoundUp is the ledger kind of both sides of a round-up contribution.
+const KindRoundUp = "round_up"
+
+// GoalProjectionWindow is how far back contributions are averaged to
+// project when a goal will be reached.
+const GoalProjectionWindow = 90 * 24 * time.Hour
+
+var (
+	ErrGoalNotFound     = errors.New("savings goal not found")
+	ErrInvalidGoal      = errors.New("goal needs a name, a positive target_amount and a target_date")
+	ErrNoGoals          = errors.New("wallet type does not support savings goals")
+	ErrInvalidRoundUp   = errors.New("round-ups must come from another wallet of the same user")
+	ErrInvalidRoundUpTo = errors.New("round_up_to must be greater than zero")
+)
+
+// Goal is a savings target on a wallet whose type allows goals. With a
+// round-up wallet, every spending debit on that wallet also moves the
+// difference to the next multiple of RoundUpTo into the goal's wallet; when
+// several goals draw on one wallet, only the oldest unfinished one does.
+type Goal struct {
+	ID              int64     `json:"id" example:"1"`
+	WalletID        int       `json:"wallet_id" example:"1"`
+	Name            string    `json:"name" example:"Holiday"`
+	TargetAmount    float64   `json:"target_amount" example:"3000.00"`
+	TargetDate      time.Time `json:"target_date" example:"2024-12-01T00:00:00Z"`
+	RoundUpWalletID *int      `json:"round_up_wallet_id,omitempty" example:"2"`
+	RoundUpTo       float64   `json:"round_up_to,omitempty" example:"1.00"`
+	CreatedAt       time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// Validate reports the first problem with g. A missing RoundUpTo defaults
+// to 1 when round-ups are enabled.
+func (g *Goal) Validate() error {
+	g.Name = strings.TrimSpace(g.Name)
+	switch {
+	case g.Name == "" || len(g.Name) > 100, g.TargetAmount <= 0, g.TargetDate.IsZero():
+		return ErrInvalidGoal
+	case g.RoundUpWalletID == nil:
+		g.RoundUpTo = 0
+	case *g.RoundUpWalletID == g.WalletID:
+		return ErrInvalidRoundUp
+	case g.RoundUpTo < 0:
+		return ErrInvalidRoundUpTo
+	case g.RoundUpTo == 0:
+		g.RoundUpTo = 1
+	}
+	return nil
+}
+
+// GoalProgress is a goal with its progress and projected completion.
+type GoalProgress struct {
+	Goal
+	Balance float64 `json:"balance" example:"1200.00"`
+	// Percent is the share of the target saved, capped at 100.
+	Percent   float64 `json:"percent" example:"40.0"`
+	Remaining float64 `json:"remaining" example:"1800.00"`
+	// DailyContribution is the average net amount added per day over the
+	// projection window.
+	DailyContribution float64 `json:"daily_contribution" example:"12.50"`
+	// ProjectedDate is when the goal will be reached at the current pace;
+	// it is omitted when the balance is not growing.
+	ProjectedDate *time.Time `json:"projected_date,omitempty" example:"2024-07-18T00:00:00Z"`
+	OnTrack       bool       `json:"on_track" example:"true"`
+	Achieved      bool       `json:"achieved" example:"false"`
+}
+
+// NewGoalProgress computes g's progress from the wallet's balance and the
+// net amount contributed over the days before now.
+func NewGoalProgress(g Goal, balance, contributed float64, days float64, now time.Time) GoalProgress {
+	p := GoalProgress{Goal: g, Balance: balance, Remaining: Round(math.Max(g.TargetAmount-balance, 0), 2)}
+	p.Percent = math.Min(math.Round(balance/g.TargetAmount*1000)/10, 100)
+	if p.Percent < 0 {
+		p.Percent = 0
+	}
+	if p.Remaining == 0 {
+		p.Percent, p.Achieved, p.OnTrack = 100, true, true
+		return p
+	}
+	if days >= 1 {
+		p.DailyContribution = Round(contributed/days, 2)
+	}
+	if p.DailyContribution > 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		projected := today.AddDate(0, 0, int(math.Ceil(p.Remaining/p.DailyContribution)))
+		p.ProjectedDate = &projected
+		p.OnTrack = !projected.After(g.TargetDate)
+	}
+	return p
+}
+
+// RoundUpGoal picks the goal that receives the round-up of a debit of
+// amount: the first of goals, which are in id order, whose round-up is not
+// zero. A debit is rounded up once however many goals draw on its wallet.
+// It returns a zero amount if no goal takes a round-up.
+func RoundUpGoal(amount float64, goals []Goal) (Goal, float64) {
+	for _, g := range goals {
+		if up := RoundUp(amount, g.RoundUpTo); up != 0 {
+			return g, up
+		}
+	}
+	return Goal{}, 0
+}
+
+// RoundUp returns the amount that takes a debit of amount up to the next
+// multiple of to, or 0 if it already is one.
+func RoundUp(amount, to float64) float64 {
+	amount = math.Abs(amount)
+	next := math.Ceil(Round(amount/to, 8)) * to
+	return Round(next-amount, 2)
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// GoalsHandler
+//
+//	@Summary		List savings goals
+//	@Description	List a wallet's savings goals with their progress and projected completion date, based on the average net contribution over the last 90 days
+//	@Tags			goal
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{array}		GoalProgress
+//	@Router			/api/v1/wallets/{id}/goals [get]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) GoalsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.GoalsHandler")
+	defer span.End()
+
+	goals, err := h.store.Goals(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, goals)
+}
+
+// GoalHandler
+//
+//	@Summary		Get savings goal
+//	@Description	Get a savings goal with its progress and projected completion date
+//	@Tags			goal
+//	@Produce		json
+//	@Param			id		path		int	true	"Wallet ID"
+//	@Param			goal_id	path		int	true	"Goal ID"
+//	@Success		200		{object}	GoalProgress
+//	@Router			/api/v1/wallets/{id}/goals/{goal_id} [get]
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) GoalHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.GoalHandler")
+	defer span.End()
+
+	goal, err := h.store.Goal(ctx, c.Param("id"), c.Param("goal_id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, goal)
+}
+
+// CreateGoalHandler
+//
+//	@Summary		Create savings goal
+//	@Description	Set a savings goal on a wallet whose type allows goals. With round_up_wallet_id, every withdrawal, capture or fee on that wallet also moves the difference to the next multiple of round_up_to (default 1) into this wallet until the goal is reached. If several goals draw on one wallet, each debit is rounded up into the oldest unfinished one only.
+//	@Tags			goal
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Wallet ID"
+//	@Param			body	body		Goal	true	"Goal"
+//	@Success		201		{object}	Goal
+//	@Router			/api/v1/wallets/{id}/goals [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateGoalHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateGoalHandler")
+	defer span.End()
+
+	g := Goal{}
+	if err := c.Bind(&g); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := g.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	g, err := h.store.CreateGoal(ctx, c.Param("id"), g)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, g)
+}
+
+// DeleteGoalHandler
+//
+//	@Summary		Delete savings goal
+//	@Description	Delete a savings goal. Money already saved stays in the wallet.
+//	@Tags			goal
+//	@Produce		json
+//	@Param			id		path		int	true	"Wallet ID"
+//	@Param			goal_id	path		int	true	"Goal ID"
+//	@Success		200		{object}	Goal
+//	@Router			/api/v1/wallets/{id}/goals/{goal_id} [delete]
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) DeleteGoalHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DeleteGoalHandler")
+	defer span.End()
+
+	goal, err := h.store.DeleteGoal(ctx, c.Param("id"), c.Param("goal_id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, goal)
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package wallet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewGoalProgress(t *testing.T) {
+	now := time.Date(2024, 3, 25, 14, 0, 0, 0, time.UTC)
+	goal := Goal{Name: "Holiday", TargetAmount: 3000, TargetDate: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("projects completion from the average contribution", func(t *testing.T) {
+		got := NewGoalProgress(goal, 1200, 900, 90, now)
+
+		if got.Percent != 40 || got.Remaining != 1800 || got.DailyContribution != 10 {
+			t.Errorf("unexpected progress %+v", got)
+		}
+		want := time.Date(2024, 9, 21, 0, 0, 0, 0, time.UTC)
+		if got.ProjectedDate == nil || !got.ProjectedDate.Equal(want) {
+			t.Fatalf("expected projected date %v but got %v", want, got.ProjectedDate)
+		}
+		if !got.OnTrack {
+			t.Error("expected goal to be on track")
+		}
+	})
+
+	t.Run("is off track when the pace is too slow", func(t *testing.T) {
+		got := NewGoalProgress(goal, 1200, 180, 90, now)
+
+		if got.ProjectedDate == nil || got.OnTrack {
+			t.Errorf("expected a projection that is off track but got %+v", got)
+		}
+	})
+
+	t.Run("has no projection when the balance is shrinking", func(t *testing.T) {
+		got := NewGoalProgress(goal, 1200, -50, 90, now)
+
+		if got.ProjectedDate != nil || got.OnTrack {
+			t.Errorf("expected no projection but got %+v", got)
+		}
+	})
+
+	t.Run("is achieved once the balance reaches the target", func(t *testing.T) {
+		got := NewGoalProgress(goal, 3500, 0, 90, now)
+
+		if !got.Achieved || got.Percent != 100 || got.Remaining != 0 {
+			t.Errorf("expected goal to be achieved but got %+v", got)
+		}
+	})
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		amount, to, want float64
+	}{
+		{-3.25, 1, 0.75},
+		{-3, 1, 0},
+		{-12.30, 5, 2.70},
+		{-0.10, 1, 0.90},
+		{-19.99, 10, 0.01},
+	}
+	for _, tt := range tests {
+		if got := RoundUp(tt.amount, tt.to); got != tt.want {
+			t.Errorf("RoundUp(%v, %v): expected %v but got %v", tt.amount, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestRoundUpGoal(t *testing.T) {
+	t.Run("given two goals should round up once into the first", func(t *testing.T) {
+		goals := []Goal{{ID: 1, WalletID: 10, RoundUpTo: 1}, {ID: 2, WalletID: 20, RoundUpTo: 5}}
+
+		g, amount := RoundUpGoal(-3.25, goals)
+
+		if g.ID != 1 || amount != 0.75 {
+			t.Errorf("expected 0.75 into goal 1 but got %v into goal %d", amount, g.ID)
+		}
+	})
+
+	t.Run("given first goal needs no round-up should use the next", func(t *testing.T) {
+		goals := []Goal{{ID: 1, WalletID: 10, RoundUpTo: 1}, {ID: 2, WalletID: 20, RoundUpTo: 5}}
+
+		g, amount := RoundUpGoal(-3, goals)
+
+		if g.ID != 2 || amount != 2 {
+			t.Errorf("expected 2 into goal 2 but got %v into goal %d", amount, g.ID)
+		}
+	})
+
+	t.Run("given no goals should not round up", func(t *testing.T) {
+		if _, amount := RoundUpGoal(-3.25, nil); amount != 0 {
+			t.Errorf("expected no round-up but got %v", amount)
+		}
+	})
+}
@@ -1,9 +1,15 @@
 package wallet
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/logging"
+	"github.com/openmymai/fun-exercise-api/tracing"
 )
 
 type Handler struct {
@@ -11,12 +17,68 @@ type Handler struct {
 }
 
 type Storer interface {
-	Wallets() ([]Wallet, error)
-	WalletsByUser(id string) ([]Wallet, error)
-	WalletsQuery(name string) ([]Wallet, error)
-	CreateWallet(wallet Wallet) (Wallet, error)
-	UpdateWallet(wallet Wallet, id string) (Wallet, error)
-	DeleteWallet(id string) error
+	Wallets(ctx context.Context) ([]Wallet, error)
+	WalletsByUser(ctx context.Context, id string) ([]Wallet, error)
+	WalletsQuery(ctx context.Context, name string) ([]Wallet, error)
+	CreateWallet(ctx context.Context, wallet Wallet) (Wallet, error)
+	UpdateWallet(ctx context.Context, wallet Wallet, id string) (Wallet, error)
+	DeleteWallet(ctx context.Context, id string) error
+	UserSummary(ctx context.Context, userID string, now time.Time) (UserSummary, error)
+	BalanceAt(ctx context.Context, walletID string, asOf time.Time) (Balance, error)
+	BalanceSeries(ctx context.Context, walletID, granularity string, from, to time.Time) (BalanceSeries, error)
+	Deposit(ctx context.Context, id string, r AmountRequest) (Transaction, error)
+	Withdraw(ctx context.Context, id string, r AmountRequest) (Transaction, error)
+	Statements(ctx context.Context, walletID string) ([]Statement, error)
+	Statement(ctx context.Context, walletID, statementID string) (Statement, error)
+	InterestPreview(ctx context.Context, walletID string, now time.Time) (InterestPreview, error)
+	Holdings(ctx context.Context, walletID string) ([]Holding, error)
+	AddHolding(ctx context.Context, walletID, asset, quantity string) (Holding, error)
+	RemoveHolding(ctx context.Context, walletID, asset, quantity string) (Holding, error)
+	Valuation(ctx context.Context, walletID, currency string) (Valuation, error)
+	Holds(ctx context.Context, walletID string) ([]Hold, error)
+	PlaceHold(ctx context.Context, walletID string, amount float64, expiresAt time.Time) (Hold, error)
+	CaptureHold(ctx context.Context, walletID, holdID string, amount float64) (Hold, error)
+	VoidHold(ctx context.Context, walletID, holdID string) (Hold, error)
+	CreateTransfer(ctx context.Context, r TransferRequest) (Transfer, error)
+	Transfer(ctx context.Context, id string) (Transfer, error)
+	RefundTransfer(ctx context.Context, id string, r RefundRequest) (Transfer, error)
+	ReverseTransfer(ctx context.Context, id string, r RefundRequest) (Transfer, error)
+	CreateScheduledTransfer(ctx context.Context, r ScheduledTransferRequest) (ScheduledTransfer, error)
+	ScheduledTransfers(ctx context.Context, walletID string) ([]ScheduledTransfer, error)
+	ScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error)
+	ScheduledRuns(ctx context.Context, id string) ([]ScheduledRun, error)
+	PauseScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error)
+	ResumeScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error)
+	CancelScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error)
+	WalletBudgets(ctx context.Context, walletID string, now time.Time) ([]BudgetStatus, error)
+	UserBudgets(ctx context.Context, userID string, now time.Time) ([]BudgetStatus, error)
+	Budget(ctx context.Context, id string, now time.Time) (BudgetStatus, error)
+	CreateBudget(ctx context.Context, b Budget) (Budget, error)
+	DeleteBudget(ctx context.Context, id string) (Budget, error)
+	BudgetAlerts(ctx context.Context, id string) ([]BudgetAlert, error)
+	Goals(ctx context.Context, walletID string, now time.Time) ([]GoalProgress, error)
+	Goal(ctx context.Context, walletID, goalID string, now time.Time) (GoalProgress, error)
+	CreateGoal(ctx context.Context, walletID string, g Goal) (Goal, error)
+	DeleteGoal(ctx context.Context, walletID, goalID string) (Goal, error)
+	Categories(ctx context.Context) ([]Category, error)
+	CreateCategory(ctx context.Context, c Category) (Category, error)
+	UpdateCategory(ctx context.Context, name string, c Category) (Category, error)
+	DeleteCategory(ctx context.Context, name string) (Category, error)
+	CategoryRules(ctx context.Context) ([]CategoryRule, error)
+	CreateCategoryRule(ctx context.Context, r CategoryRule) (CategoryRule, error)
+	DeleteCategoryRule(ctx context.Context, id string) (CategoryRule, error)
+	WalletSpending(ctx context.Context, walletID string, from, to time.Time) (SpendingReport, error)
+	UserSpending(ctx context.Context, userID string, from, to time.Time) (SpendingReport, error)
+	CheckLedger(ctx context.Context, now time.Time) (LedgerCheck, error)
+	LedgerAccounts(ctx context.Context) ([]Account, error)
+	JournalEntry(ctx context.Context, id string) (JournalEntry, error)
+	Reconcile(ctx context.Context, fix bool) (ReconciliationReport, error)
+	ReconciliationReports(ctx context.Context) ([]ReconciliationReport, error)
+	ReconciliationReport(ctx context.Context, id string) (ReconciliationReport, error)
+	WalletTypes(ctx context.Context) ([]Type, error)
+	CreateWalletType(ctx context.Context, t Type) (Type, error)
+	UpdateWalletType(ctx context.Context, name string, t Type) (Type, error)
+	RetireWalletType(ctx context.Context, name string) (Type, error)
 }
 
 func New(db Storer) *Handler {
@@ -24,7 +86,53 @@ func New(db Storer) *Handler {
 }
 
 type Err struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// errorJSON writes err as an Err response tagged with the request ID. Server
+// errors are logged as well since clients only see the message.
+func errorJSON(c echo.Context, code int, err error) error {
+	ctx := c.Request().Context()
+	if code >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "request failed", "error", err)
+	}
+	return c.JSON(code, Err{Message: err.Error(), RequestID: logging.RequestID(ctx)})
+}
+
+// storeError maps errors returned by the store to a response.
+func storeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrTypeNotFound), errors.Is(err, ErrStatementNotFound),
+		errors.Is(err, ErrHoldingNotFound), errors.Is(err, ErrHoldNotFound),
+		errors.Is(err, ErrTransferNotFound), errors.Is(err, ErrScheduledTransferNotFound),
+		errors.Is(err, ErrBudgetNotFound), errors.Is(err, ErrGoalNotFound),
+		errors.Is(err, ErrCategoryNotFound), errors.Is(err, ErrRuleNotFound), errors.Is(err, ErrEntryNotFound),
+		errors.Is(err, ErrReportNotFound):
+		return errorJSON(c, http.StatusNotFound, err)
+	case errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrPrecision),
+		errors.Is(err, ErrInvalidWalletType), errors.Is(err, ErrUnknownWalletType),
+		errors.Is(err, ErrInvalidAsset), errors.Is(err, ErrInvalidQuantity), errors.Is(err, ErrUnknownCurrency),
+		errors.Is(err, ErrInvalidExpiry), errors.Is(err, ErrSameWallet), errors.Is(err, ErrInvalidSchedule),
+		errors.Is(err, ErrInvalidBudget), errors.Is(err, ErrInvalidGoal),
+		errors.Is(err, ErrInvalidRoundUp), errors.Is(err, ErrInvalidRoundUpTo),
+		errors.Is(err, ErrInvalidCategory), errors.Is(err, ErrUnknownCategory), errors.Is(err, ErrInvalidRule),
+		errors.Is(err, ErrInvalidTags), errors.Is(err, ErrInvalidMetadata), errors.Is(err, ErrInvalidDateRange),
+		errors.Is(err, ErrInvalidCurrency), errors.Is(err, ErrInvalidAsOf),
+		errors.Is(err, ErrInvalidGranularity), errors.Is(err, ErrSeriesTooLong), errors.Is(err, ErrInvalidReason):
+		return errorJSON(c, http.StatusBadRequest, err)
+	case errors.Is(err, ErrTypeExists), errors.Is(err, ErrCategoryExists), errors.Is(err, ErrCategoryInUse):
+		return errorJSON(c, http.StatusConflict, err)
+	case errors.Is(err, ErrInsufficientFunds), errors.Is(err, ErrCreditLimitExceeded), errors.Is(err, ErrWithdrawalCap),
+		errors.Is(err, ErrTypeRetired), errors.Is(err, ErrNoInterest),
+		errors.Is(err, ErrNoHoldings), errors.Is(err, ErrInsufficientHolding),
+		errors.Is(err, ErrHoldNotActive), errors.Is(err, ErrCaptureExceedsHold),
+		errors.Is(err, ErrScheduleState), errors.Is(err, ErrNoGoals),
+		errors.Is(err, ErrCurrencyChange), errors.Is(err, ErrCurrencyMismatch),
+		errors.Is(err, ErrTransferRefunded), errors.Is(err, ErrRefundExceedsAmount):
+		return errorJSON(c, http.StatusUnprocessableEntity, err)
+	}
+	return errorJSON(c, http.StatusInternalServerError, err)
 }
 
 // WalletHandler
@@ -38,9 +146,12 @@ type Err struct {
 //	@Router			/api/v1/wallets [get]
 //	@Failure		500	{object}	Err
 func (h *Handler) WalletsHandler(c echo.Context) error {
-	wallets, err := h.store.Wallets()
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletsHandler")
+	defer span.End()
+
+	wallets, err := h.store.Wallets(ctx)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusInternalServerError, err)
 	}
 	return c.JSON(http.StatusOK, wallets)
 }
@@ -56,15 +167,40 @@ func (h *Handler) WalletsHandler(c echo.Context) error {
 //	@Router			/api/v1/users/:id/wallets [get]
 //	@Failure		500	{object}	Err
 func (h *Handler) WalletsByUserHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletsByUserHandler")
+	defer span.End()
+
 	id := c.Param("id")
-	wallets, err := h.store.WalletsByUser(id)
+	wallets, err := h.store.WalletsByUser(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, wallets)
 }
 
+// UserSummaryHandler
+//
+//	@Summary		Get user portfolio summary
+//	@Description	Summarise a user's wallets: wallet count, assets, credit card debt and net worth per currency with the change since the start of the month, and balances per wallet type
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	UserSummary
+//	@Router			/api/v1/users/{id}/summary [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) UserSummaryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UserSummaryHandler")
+	defer span.End()
+
+	summary, err := h.store.UserSummary(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
 // WalletTypeQueryHandler
 //
 //	@Summary		Get wallets by WalletType
@@ -77,10 +213,13 @@ func (h *Handler) WalletsByUserHandler(c echo.Context) error {
 //	@Router			/api/v1/wallets/wallet [get]
 //	@Failure		500	{object}	Err
 func (h *Handler) WalletsTypeQueryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletsTypeQueryHandler")
+	defer span.End()
+
 	name := c.QueryParam("wallet_type")
-	wallets, err := h.store.WalletsQuery(name)
+	wallets, err := h.store.WalletsQuery(ctx, name)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, wallets)
@@ -95,16 +234,21 @@ func (h *Handler) WalletsTypeQueryHandler(c echo.Context) error {
 //	@Produce		json
 //	@Success		200	{object}	Wallet
 //	@Router			/api/v1/wallets [post]
+//	@Failure		400	{object}	Err
+//	@Failure		422	{object}	Err
 //	@Failure		500	{object}	Err
 func (h *Handler) CreateWalletHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateWalletHandler")
+	defer span.End()
+
 	w := Wallet{}
 	err := c.Bind(&w)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusBadRequest, err)
 	}
-	wallet, err := h.store.CreateWallet(w)
+	wallet, err := h.store.CreateWallet(ctx, w)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return storeError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, wallet)
@@ -119,18 +263,24 @@ func (h *Handler) CreateWalletHandler(c echo.Context) error {
 //	@Produce		json
 //	@Success		200	{object}	Wallet
 //	@Router			/api/v1/wallets [put]
+//	@Failure		400	{object}	Err
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
 //	@Failure		500	{object}	Err
 func (h *Handler) UpdateWalletHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UpdateWalletHandler")
+	defer span.End()
+
 	id := c.Param("id")
 
 	wallet := Wallet{}
 	err := c.Bind(&wallet)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusBadRequest, err)
 	}
-	updateWallet, err := h.store.UpdateWallet(wallet, id)
+	updateWallet, err := h.store.UpdateWallet(ctx, wallet, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return storeError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, updateWallet)
@@ -147,12 +297,500 @@ func (h *Handler) UpdateWalletHandler(c echo.Context) error {
 //	@Router			/api/v1/users/:id/wallets [delete]
 //	@Failure		500	{object}	Err
 func (h *Handler) DeleteWalletHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DeleteWalletHandler")
+	defer span.End()
+
 	id := c.Param("id")
 
-	err := h.store.DeleteWallet(id)
+	err := h.store.DeleteWallet(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: err.Error()})
+		return errorJSON(c, http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, "Delete "+id+" successful")
 }
+
+// DepositHandler
+//
+//	@Summary		Deposit into wallet
+//	@Description	Add money to a wallet and record the deposit in its ledger, with optional category, merchant, description and tags
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Wallet ID"
+//	@Param			body	body		AmountRequest	true	"Amount to deposit"
+//	@Success		201		{object}	MovementResult
+//	@Router			/api/v1/wallets/{id}/deposits [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) DepositHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.DepositHandler")
+	defer span.End()
+
+	return h.move(c, func(id string, req AmountRequest) (Transaction, error) {
+		return h.store.Deposit(ctx, id, req)
+	})
+}
+
+// WithdrawalHandler
+//
+//	@Summary		Withdraw from wallet
+//	@Description	Take money out of a wallet and record the withdrawal in its ledger, subject to the wallet type's rules: Credit Card wallets may go negative down to their credit limit, Savings wallets have a monthly cap on withdrawals, transfers out, captures and round-ups together. The category counts the withdrawal against matching budgets; without one, category rules may pick it from the merchant or description.
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Wallet ID"
+//	@Param			body	body		AmountRequest	true	"Amount to withdraw"
+//	@Success		201		{object}	MovementResult
+//	@Router			/api/v1/wallets/{id}/withdrawals [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) WithdrawalHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WithdrawalHandler")
+	defer span.End()
+
+	return h.move(c, func(id string, req AmountRequest) (Transaction, error) {
+		return h.store.Withdraw(ctx, id, req)
+	})
+}
+
+func (h *Handler) move(c echo.Context, apply func(id string, req AmountRequest) (Transaction, error)) error {
+	id := c.Param("id")
+
+	req := AmountRequest{}
+	err := c.Bind(&req)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	t, err := apply(id, req)
+	if err != nil {
+		return storeError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, MovementResult{Balance: t.BalanceAfter, Transaction: t})
+}
+
+// StatementsHandler
+//
+//	@Summary		List statements
+//	@Description	List a credit card wallet's statements, newest first
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{array}		Statement
+//	@Router			/api/v1/wallets/{id}/statements [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) StatementsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.StatementsHandler")
+	defer span.End()
+
+	statements, err := h.store.Statements(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, statements)
+}
+
+// StatementHandler
+//
+//	@Summary		Get statement
+//	@Description	Get a statement together with the ledger entries of its billing period
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id				path		int	true	"Wallet ID"
+//	@Param			statement_id	path		int	true	"Statement ID"
+//	@Success		200				{object}	Statement
+//	@Router			/api/v1/wallets/{id}/statements/{statement_id} [get]
+//	@Failure		404				{object}	Err
+//	@Failure		500				{object}	Err
+func (h *Handler) StatementHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.StatementHandler")
+	defer span.End()
+
+	statement, err := h.store.Statement(ctx, c.Param("id"), c.Param("statement_id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, statement)
+}
+
+// InterestPreviewHandler
+//
+//	@Summary		Preview interest
+//	@Description	Dry run of the monthly interest posting: show the interest accrued but not yet posted and what the current balance earns per day. Nothing is written.
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{object}	InterestPreview
+//	@Router			/api/v1/wallets/{id}/interest/preview [get]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) InterestPreviewHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.InterestPreviewHandler")
+	defer span.End()
+
+	preview, err := h.store.InterestPreview(ctx, c.Param("id"), time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, preview)
+}
+
+// HoldingsHandler
+//
+//	@Summary		List holdings
+//	@Description	List the assets held by a wallet whose type holds assets, e.g. a Crypto Wallet
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{array}		Holding
+//	@Router			/api/v1/wallets/{id}/holdings [get]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) HoldingsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.HoldingsHandler")
+	defer span.End()
+
+	holdings, err := h.store.Holdings(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, holdings)
+}
+
+// AddHoldingHandler
+//
+//	@Summary		Add to holding
+//	@Description	Add a quantity of an asset to a wallet. Quantities are decimal strings with up to 18 decimal places.
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Wallet ID"
+//	@Param			body	body		HoldingRequest	true	"Asset and quantity"
+//	@Success		201		{object}	Holding
+//	@Router			/api/v1/wallets/{id}/holdings [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) AddHoldingHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.AddHoldingHandler")
+	defer span.End()
+
+	req := HoldingRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	asset, err := NormalizeAsset(req.Asset)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	quantity, err := ParseQuantity(req.Quantity)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	holding, err := h.store.AddHolding(ctx, c.Param("id"), asset, quantity)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, holding)
+}
+
+// RemoveHoldingHandler
+//
+//	@Summary		Remove from holding
+//	@Description	Remove a quantity of an asset from a wallet, or the whole holding when no quantity is given
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id			path		int		true	"Wallet ID"
+//	@Param			asset		path		string	true	"Asset code, e.g. BTC"
+//	@Param			quantity	query		string	false	"Quantity to remove"
+//	@Success		200			{object}	Holding
+//	@Router			/api/v1/wallets/{id}/holdings/{asset} [delete]
+//	@Failure		400			{object}	Err
+//	@Failure		404			{object}	Err
+//	@Failure		422			{object}	Err
+//	@Failure		500			{object}	Err
+func (h *Handler) RemoveHoldingHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.RemoveHoldingHandler")
+	defer span.End()
+
+	asset, err := NormalizeAsset(c.Param("asset"))
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	quantity := c.QueryParam("quantity")
+	if quantity != "" {
+		if quantity, err = ParseQuantity(quantity); err != nil {
+			return errorJSON(c, http.StatusBadRequest, err)
+		}
+	}
+
+	holding, err := h.store.RemoveHolding(ctx, c.Param("id"), asset, quantity)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, holding)
+}
+
+// ValuationHandler
+//
+//	@Summary		Value holdings
+//	@Description	Price a wallet's holdings in a fiat currency using the configured price table
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id			path		int		true	"Wallet ID"
+//	@Param			currency	query		string	false	"Fiat currency, e.g. USD; defaults to the configured currency"
+//	@Success		200			{object}	Valuation
+//	@Router			/api/v1/wallets/{id}/valuation [get]
+//	@Failure		400			{object}	Err
+//	@Failure		404			{object}	Err
+//	@Failure		422			{object}	Err
+//	@Failure		500			{object}	Err
+func (h *Handler) ValuationHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ValuationHandler")
+	defer span.End()
+
+	valuation, err := h.store.Valuation(ctx, c.Param("id"), c.QueryParam("currency"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, valuation)
+}
+
+// HoldsHandler
+//
+//	@Summary		List holds
+//	@Description	List a wallet's holds, newest first
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id	path		int	true	"Wallet ID"
+//	@Success		200	{array}		Hold
+//	@Router			/api/v1/wallets/{id}/holds [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) HoldsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.HoldsHandler")
+	defer span.End()
+
+	holds, err := h.store.Holds(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, holds)
+}
+
+// PlaceHoldHandler
+//
+//	@Summary		Place hold
+//	@Description	Reserve funds on a wallet until they are captured, voided or the hold expires. Held funds are not available to other debits.
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int			true	"Wallet ID"
+//	@Param			body	body		HoldRequest	true	"Amount and expiry"
+//	@Success		201		{object}	Hold
+//	@Router			/api/v1/wallets/{id}/holds [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) PlaceHoldHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.PlaceHoldHandler")
+	defer span.End()
+
+	req := HoldRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if req.Amount <= 0 {
+		return errorJSON(c, http.StatusBadRequest, ErrInvalidAmount)
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		return errorJSON(c, http.StatusBadRequest, ErrInvalidExpiry)
+	}
+
+	hold, err := h.store.PlaceHold(ctx, c.Param("id"), req.Amount, req.ExpiresAt)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, hold)
+}
+
+// CaptureHoldHandler
+//
+//	@Summary		Capture hold
+//	@Description	Settle an active hold as a debit in the wallet's ledger. A smaller amount captures part of the hold and releases the rest; no amount captures all of it.
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Wallet ID"
+//	@Param			hold_id	path		int				true	"Hold ID"
+//	@Param			body	body		CaptureRequest	false	"Amount to capture"
+//	@Success		200		{object}	Hold
+//	@Router			/api/v1/wallets/{id}/holds/{hold_id}/capture [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CaptureHoldHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CaptureHoldHandler")
+	defer span.End()
+
+	req := CaptureRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if req.Amount < 0 {
+		return errorJSON(c, http.StatusBadRequest, ErrInvalidAmount)
+	}
+
+	hold, err := h.store.CaptureHold(ctx, c.Param("id"), c.Param("hold_id"), req.Amount)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, hold)
+}
+
+// VoidHoldHandler
+//
+//	@Summary		Void hold
+//	@Description	Release an active hold without moving money
+//	@Tags			wallet
+//	@Produce		json
+//	@Param			id		path		int	true	"Wallet ID"
+//	@Param			hold_id	path		int	true	"Hold ID"
+//	@Success		200		{object}	Hold
+//	@Router			/api/v1/wallets/{id}/holds/{hold_id}/void [post]
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) VoidHoldHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.VoidHoldHandler")
+	defer span.End()
+
+	hold, err := h.store.VoidHold(ctx, c.Param("id"), c.Param("hold_id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, hold)
+}
+
+// WalletTypesHandler
+//
+//	@Summary		List wallet types
+//	@Description	List wallet types and their balance rules, including retired ones
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		Type
+//	@Router			/api/v1/admin/wallet-types [get]
+//	@Failure		401	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) WalletTypesHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.WalletTypesHandler")
+	defer span.End()
+
+	types, err := h.store.WalletTypes(ctx)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, types)
+}
+
+// CreateWalletTypeHandler
+//
+//	@Summary		Create wallet type
+//	@Description	Create a wallet type with its balance rules
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		Type	true	"Wallet type"
+//	@Success		201		{object}	Type
+//	@Router			/api/v1/admin/wallet-types [post]
+//	@Failure		400		{object}	Err
+//	@Failure		401		{object}	Err
+//	@Failure		409		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateWalletTypeHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateWalletTypeHandler")
+	defer span.End()
+
+	t := Type{}
+	if err := c.Bind(&t); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	t, err := h.store.CreateWalletType(ctx, t)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, t)
+}
+
+// UpdateWalletTypeHandler
+//
+//	@Summary		Update wallet type
+//	@Description	Replace a wallet type's balance rules. A different name in the body renames the type and all its wallets.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string	true	"Wallet type name"
+//	@Param			body	body		Type	true	"Wallet type"
+//	@Success		200		{object}	Type
+//	@Router			/api/v1/admin/wallet-types/{name} [put]
+//	@Failure		400		{object}	Err
+//	@Failure		401		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		409		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) UpdateWalletTypeHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.UpdateWalletTypeHandler")
+	defer span.End()
+
+	name := c.Param("name")
+	t := Type{}
+	if err := c.Bind(&t); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if t.Name == "" {
+		t.Name = name
+	}
+	t, err := h.store.UpdateWalletType(ctx, name, t)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// RetireWalletTypeHandler
+//
+//	@Summary		Retire wallet type
+//	@Description	Stop new wallets from using a type. Existing wallets keep it.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			name	path		string	true	"Wallet type name"
+//	@Success		200		{object}	Type
+//	@Router			/api/v1/admin/wallet-types/{name} [delete]
+//	@Failure		401		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) RetireWalletTypeHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.RetireWalletTypeHandler")
+	defer span.End()
+
+	t, err := h.store.RetireWalletType(ctx, c.Param("name"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, t)
+}
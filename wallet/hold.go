@@ -0,0 +1,55 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+)
+
+// Hold statuses. Only active holds reserve funds.
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+)
+
+const (
+	// KindCapture is the ledger entry of a captured hold.
+	KindCapture = "capture"
+	// KindHold is used to check a new hold against the wallet's policy
+	// like a debit. Holds themselves are not ledger entries.
+	KindHold = "hold"
+)
+
+var (
+	ErrHoldNotFound       = errors.New("hold not found")
+	ErrHoldNotActive      = errors.New("hold is no longer active")
+	ErrInvalidExpiry      = errors.New("expires_at must be in the future")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds hold")
+)
+
+// Hold reserves part of a wallet's balance until it is captured, voided or
+// expires.
+type Hold struct {
+	ID             int64     `json:"id" example:"1"`
+	WalletID       int       `json:"wallet_id" example:"1"`
+	Amount         float64   `json:"amount" example:"40.00"`
+	Status         string    `json:"status" example:"active"`
+	CapturedAmount float64   `json:"captured_amount,omitempty" example:"35.50"`
+	TransactionID  *int64    `json:"transaction_id,omitempty" example:"12"`
+	ExpiresAt      time.Time `json:"expires_at" example:"2024-03-26T14:19:00Z"`
+	CreatedAt      time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+	UpdatedAt      time.Time `json:"updated_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// HoldRequest is the body of requests placing a hold.
+type HoldRequest struct {
+	Amount    float64   `json:"amount" example:"40.00"`
+	ExpiresAt time.Time `json:"expires_at" example:"2024-03-26T14:19:00Z"`
+}
+
+// CaptureRequest is the body of capture requests. A zero amount captures
+// the whole hold.
+type CaptureRequest struct {
+	Amount float64 `json:"amount" example:"35.50"`
+}
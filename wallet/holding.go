@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QuantityDecimals is the precision of asset quantities.
+const QuantityDecimals = 18
+
+// fiatDecimals is the precision of valuations.
+const fiatDecimals = 2
+
+var (
+	ErrInvalidAsset        = errors.New("asset must be 2 to 16 upper-case letters or digits")
+	ErrInvalidQuantity     = errors.New("quantity must be a positive decimal with at most 18 decimal places")
+	ErrHoldingNotFound     = errors.New("holding not found")
+	ErrInsufficientHolding = errors.New("quantity exceeds holding")
+	ErrNoHoldings          = errors.New("wallet type does not hold assets")
+	ErrUnknownCurrency     = errors.New("no prices for currency")
+)
+
+var assetPattern = regexp.MustCompile(`^[A-Z0-9]{2,16}$`)
+
+// Holding is a wallet's quantity of one asset. Quantities are decimal
+// strings so that no precision is lost in JSON.
+type Holding struct {
+	WalletID  int       `json:"wallet_id" example:"1"`
+	Asset     string    `json:"asset" example:"BTC"`
+	Quantity  string    `json:"quantity" example:"0.000123450000000000"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// HoldingRequest is the body of requests adding to a holding.
+type HoldingRequest struct {
+	Asset    string `json:"asset" example:"BTC"`
+	Quantity string `json:"quantity" example:"0.00012345"`
+}
+
+// Valuation prices a wallet's holdings in a fiat currency.
+type Valuation struct {
+	WalletID int            `json:"wallet_id" example:"1"`
+	Currency string         `json:"currency" example:"USD"`
+	Total    string         `json:"total" example:"8.02"`
+	Holdings []HoldingValue `json:"holdings"`
+	// Unpriced lists assets without a price in the currency; they are not
+	// part of the total.
+	Unpriced []string `json:"unpriced,omitempty" example:"DOGE"`
+}
+
+type HoldingValue struct {
+	Asset    string `json:"asset" example:"BTC"`
+	Quantity string `json:"quantity" example:"0.000123450000000000"`
+	Price    string `json:"price" example:"65000.00"`
+	Value    string `json:"value" example:"8.02"`
+}
+
+// NormalizeAsset upper-cases and validates an asset code.
+func NormalizeAsset(asset string) (string, error) {
+	asset = strings.ToUpper(strings.TrimSpace(asset))
+	if !assetPattern.MatchString(asset) {
+		return "", ErrInvalidAsset
+	}
+	return asset, nil
+}
+
+var quantityScale = new(big.Int).Exp(big.NewInt(10), big.NewInt(QuantityDecimals), nil)
+
+// ParseQuantity parses a positive decimal quantity of at most
+// QuantityDecimals decimal places and returns it in canonical form.
+func ParseQuantity(s string) (string, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return "", ErrInvalidQuantity
+	}
+	// r has at most QuantityDecimals places if its denominator divides
+	// 10^QuantityDecimals.
+	if new(big.Int).Mod(quantityScale, r.Denom()).Sign() != 0 {
+		return "", ErrInvalidQuantity
+	}
+	return r.FloatString(QuantityDecimals), nil
+}
+
+// Value prices holdings with prices, which maps assets to the price of one
+// unit in currency. Values are exact until rounded for display.
+func Value(walletID int, holdings []Holding, currency string, prices map[string]string) (Valuation, error) {
+	if prices == nil {
+		return Valuation{}, fmt.Errorf("%w %s", ErrUnknownCurrency, currency)
+	}
+	v := Valuation{WalletID: walletID, Currency: currency, Holdings: []HoldingValue{}}
+	total := new(big.Rat)
+	for _, h := range holdings {
+		qty, ok := new(big.Rat).SetString(h.Quantity)
+		if !ok {
+			return Valuation{}, fmt.Errorf("holding %s: invalid quantity %q", h.Asset, h.Quantity)
+		}
+		price, ok := new(big.Rat).SetString(prices[h.Asset])
+		if !ok {
+			v.Unpriced = append(v.Unpriced, h.Asset)
+			continue
+		}
+		value := new(big.Rat).Mul(qty, price)
+		total.Add(total, value)
+		v.Holdings = append(v.Holdings, HoldingValue{
+			Asset:    h.Asset,
+			Quantity: h.Quantity,
+			Price:    prices[h.Asset],
+			Value:    value.FloatString(fiatDecimals),
+		})
+	}
+	sort.Strings(v.Unpriced)
+	v.Total = total.FloatString(fiatDecimals)
+	return v, nil
+}
@@ -0,0 +1,65 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"0.00012345", "0.000123450000000000", nil},
+		{"1.000000000000000001", "1.000000000000000001", nil},
+		{"1.0000000000000000001", "", ErrInvalidQuantity},
+		{"0", "", ErrInvalidQuantity},
+		{"-1", "", ErrInvalidQuantity},
+		{"1/3", "", ErrInvalidQuantity},
+		{"abc", "", ErrInvalidQuantity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseQuantity(tt.in)
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected error %v but got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	holdings := []Holding{
+		{Asset: "BTC", Quantity: "0.000123450000000000"},
+		{Asset: "ETH", Quantity: "1.500000000000000000"},
+		{Asset: "DOGE", Quantity: "100"},
+	}
+	prices := map[string]string{"BTC": "65000.00", "ETH": "3500.10"}
+
+	got, err := Value(1, holdings, "USD", prices)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Valuation{
+		WalletID: 1,
+		Currency: "USD",
+		Total:    "5258.17",
+		Holdings: []HoldingValue{
+			{Asset: "BTC", Quantity: "0.000123450000000000", Price: "65000.00", Value: "8.02"},
+			{Asset: "ETH", Quantity: "1.500000000000000000", Price: "3500.10", Value: "5250.15"},
+		},
+		Unpriced: []string{"DOGE"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v but got %+v", want, got)
+	}
+}
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+const KindInterest = "interest"
+
+var ErrNoInterest = errors.New("wallet type does not earn interest")
+
+// daysPerYear converts annual rates to daily ones.
+const daysPerYear = 365
+
+// InterestPreview shows what the next monthly posting would pay a wallet
+// if it ran now. Nothing is written.
+type InterestPreview struct {
+	WalletID int     `json:"wallet_id" example:"1"`
+	Balance  float64 `json:"balance" example:"12000.00"`
+	// AnnualRate is the effective rate on the current balance.
+	AnnualRate float64 `json:"annual_rate" example:"0.010833"`
+	// DailyInterest is what a day at the current balance accrues.
+	DailyInterest float64 `json:"daily_interest" example:"0.35616438"`
+	// AccruedDays and Accrued cover the days accrued but not yet posted.
+	AccruedDays int        `json:"accrued_days" example:"12"`
+	Accrued     float64    `json:"accrued" example:"4.27"`
+	AccruedFrom *time.Time `json:"accrued_from,omitempty" example:"2024-03-01T00:00:00Z"`
+	AccruedTo   *time.Time `json:"accrued_to,omitempty" example:"2024-03-12T00:00:00Z"`
+	// NextPosting is the first day of next month, when the accrued
+	// interest of this month is posted.
+	NextPosting time.Time `json:"next_posting" example:"2024-04-01T00:00:00Z"`
+}
+
+// AnnualInterest returns a year's interest on balance under marginal tiers.
+// Negative balances earn nothing.
+func AnnualInterest(balance float64, tiers []config.InterestTier) float64 {
+	var interest float64
+	for i, t := range tiers {
+		if balance <= t.From {
+			break
+		}
+		upper := balance
+		if i+1 < len(tiers) && tiers[i+1].From < balance {
+			upper = tiers[i+1].From
+		}
+		interest += (upper - t.From) * t.Rate
+	}
+	return interest
+}
+
+// DailyInterest returns a day's interest on balance, unrounded.
+func DailyInterest(balance float64, tiers []config.InterestTier) float64 {
+	return AnnualInterest(balance, tiers) / daysPerYear
+}
+
+// Round rounds amount to the given number of decimal places.
+func Round(amount float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(amount*scale) / scale
+}
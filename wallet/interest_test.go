@@ -0,0 +1,38 @@
+//go:build unit
+
+package wallet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+func TestAnnualInterest(t *testing.T) {
+	tiers := []config.InterestTier{
+		{From: 0, Rate: 0.01},
+		{From: 10000, Rate: 0.015},
+		{From: 50000, Rate: 0.02},
+	}
+
+	tests := []struct {
+		name    string
+		balance float64
+		want    float64
+	}{
+		{"negative balance", -100, 0},
+		{"first tier", 5000, 50},
+		{"second tier is marginal", 12000, 100 + 30},
+		{"all tiers", 60000, 100 + 600 + 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnnualInterest(tt.balance, tiers)
+
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
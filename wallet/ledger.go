@@ -0,0 +1,133 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrEntryNotFound = errors.New("journal entry not found")
+
+// Account kinds. Every wallet has its own account; system accounts stand
+// for the world outside the wallets and exist once per currency.
+const (
+	AccountWallet = "wallet"
+	AccountSystem = "system"
+)
+
+// System accounts. There is no FX account: movements between wallets in
+// different currencies are rejected with ErrCurrencyMismatch, so every
+// journal entry stays within one currency. Currency conversion would need
+// one, with an entry posting to it in both currencies.
+const (
+	// SystemExternal is money entering or leaving through deposits,
+	// withdrawals and captured card holds.
+	SystemExternal = "external"
+	SystemFees     = "fees"
+	SystemInterest = "interest"
+	// SystemClearing carries money between wallets. Each transfer, refund
+	// or round-up posts both of its legs through it, so it nets to zero once
+	// both have been recorded.
+	SystemClearing = "clearing"
+	// SystemAdjustments balances opening balances and manual corrections.
+	SystemAdjustments = "adjustments"
+)
+
+// CounterAccount returns the system account on the other side of a ledger
+// entry of kind.
+func CounterAccount(kind string) string {
+	switch kind {
+	case KindFee:
+		return SystemFees
+	case KindInterest:
+		return SystemInterest
+	case KindTransferOut, KindTransferIn, KindRefundOut, KindRefundIn, KindRoundUp:
+		return SystemClearing
+	case KindOpening, KindAdjustment:
+		return SystemAdjustments
+	default:
+		return SystemExternal
+	}
+}
+
+// SystemAccountCode is the code of the system account name in currency.
+func SystemAccountCode(name, currency string) string {
+	return name + ":" + currency
+}
+
+// Account is a ledger account. Its balance is the sum of its postings; a
+// wallet account's balance equals the wallet's balance.
+type Account struct {
+	ID       int64   `json:"id" example:"7"`
+	Code     string  `json:"code" example:"fees:USD"`
+	Kind     string  `json:"kind" example:"system"`
+	Currency string  `json:"currency" example:"USD"`
+	Balance  float64 `json:"balance" example:"-35.00"`
+}
+
+// Posting is one leg of a journal entry. Amounts are signed: positive
+// postings increase a wallet's balance.
+type Posting struct {
+	AccountID   int64   `json:"account_id" example:"3"`
+	AccountCode string  `json:"account_code" example:"wallet:1"`
+	Amount      float64 `json:"amount" example:"100.00"`
+}
+
+// JournalEntry records one wallet transaction as postings that sum to zero.
+type JournalEntry struct {
+	ID            int64     `json:"id" example:"42"`
+	TransactionID int64     `json:"transaction_id" example:"42"`
+	Kind          string    `json:"kind" example:"deposit"`
+	Postings      []Posting `json:"postings"`
+	CreatedAt     time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// LedgerCheck is the result of checking the ledger's invariants: postings
+// sum to zero overall and within every entry, every wallet's cached balance
+// equals the sum of its account's postings, and every transaction has been
+// posted. Lists are capped, so a count is reported alongside each.
+type LedgerCheck struct {
+	CheckedAt time.Time `json:"checked_at" example:"2024-03-25T14:19:00Z"`
+	OK        bool      `json:"ok" example:"true"`
+	// Totals sums all postings per currency. Each should be zero.
+	Totals            []CurrencyTotal   `json:"totals"`
+	UnbalancedCount   int               `json:"unbalanced_count" example:"0"`
+	UnbalancedEntries []UnbalancedEntry `json:"unbalanced_entries"`
+	MismatchCount     int               `json:"mismatch_count" example:"0"`
+	BalanceMismatches []BalanceMismatch `json:"balance_mismatches"`
+	UnpostedCount     int               `json:"unposted_count" example:"0"`
+	// Clearing lists clearing accounts that do not net to zero, which is
+	// expected only while a transfer is being recorded or after a wallet
+	// on one side of it was deleted.
+	Clearing []CurrencyTotal `json:"clearing"`
+}
+
+// CurrencyTotal is a sum of postings in one currency.
+type CurrencyTotal struct {
+	Currency string  `json:"currency" example:"USD"`
+	Total    float64 `json:"total" example:"0"`
+}
+
+// UnbalancedEntry is a journal entry whose postings do not sum to zero.
+type UnbalancedEntry struct {
+	EntryID int64   `json:"entry_id" example:"42"`
+	Total   float64 `json:"total" example:"0.01"`
+}
+
+// BalanceMismatch is a wallet whose cached balance differs from the
+// balance derived from its postings.
+type BalanceMismatch struct {
+	WalletID int     `json:"wallet_id" example:"1"`
+	Cached   float64 `json:"cached" example:"100.00"`
+	Derived  float64 `json:"derived" example:"90.00"`
+}
+
+// Passed reports whether every invariant holds. Clearing balances are
+// reported but do not fail the check.
+func (l LedgerCheck) Passed() bool {
+	for _, t := range l.Totals {
+		if t.Total != 0 {
+			return false
+		}
+	}
+	return l.UnbalancedCount == 0 && l.MismatchCount == 0 && l.UnpostedCount == 0
+}
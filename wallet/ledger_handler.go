@@ -0,0 +1,71 @@
+package wallet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// LedgerCheckHandler
+//
+//	@Summary		Check ledger invariants
+//	@Description	Check that all postings, and the postings of every journal entry, sum to zero, that every wallet's balance equals the sum of its account's postings and that every transaction has been posted
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	LedgerCheck
+//	@Router			/api/v1/admin/ledger/check [get]
+//	@Failure		500	{object}	Err
+func (h *Handler) LedgerCheckHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.LedgerCheckHandler")
+	defer span.End()
+
+	check, err := h.store.CheckLedger(ctx, time.Now())
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, check)
+}
+
+// LedgerAccountsHandler
+//
+//	@Summary		List system accounts
+//	@Description	List the system ledger accounts, such as fees and external funding, with their balances
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		Account
+//	@Router			/api/v1/admin/ledger/accounts [get]
+//	@Failure		500	{object}	Err
+func (h *Handler) LedgerAccountsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.LedgerAccountsHandler")
+	defer span.End()
+
+	accounts, err := h.store.LedgerAccounts(ctx)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, accounts)
+}
+
+// JournalEntryHandler
+//
+//	@Summary		Get journal entry
+//	@Description	Get a journal entry with its postings
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"Journal entry ID"
+//	@Success		200	{object}	JournalEntry
+//	@Router			/api/v1/admin/ledger/entries/{id} [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) JournalEntryHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.JournalEntryHandler")
+	defer span.End()
+
+	entry, err := h.store.JournalEntry(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, entry)
+}
@@ -0,0 +1,52 @@
+//go:build unit
+
+package wallet
+
+import "testing"
+
+func TestCounterAccount(t *testing.T) {
+	tests := []struct {
+		kind string
+		want string
+	}{
+		{KindDeposit, SystemExternal},
+		{KindWithdrawal, SystemExternal},
+		{KindCapture, SystemExternal},
+		{KindFee, SystemFees},
+		{KindInterest, SystemInterest},
+		{KindTransferOut, SystemClearing},
+		{KindTransferIn, SystemClearing},
+		{KindRefundOut, SystemClearing},
+		{KindRefundIn, SystemClearing},
+		{KindRoundUp, SystemClearing},
+		{KindOpening, SystemAdjustments},
+		{KindAdjustment, SystemAdjustments},
+	}
+	for _, tt := range tests {
+		if got := CounterAccount(tt.kind); got != tt.want {
+			t.Errorf("CounterAccount(%q): expected %q but got %q", tt.kind, tt.want, got)
+		}
+	}
+}
+
+func TestLedgerCheckPassed(t *testing.T) {
+	balanced := LedgerCheck{
+		Totals:   []CurrencyTotal{{Currency: "USD", Total: 0}},
+		Clearing: []CurrencyTotal{{Currency: "USD", Total: 25}},
+	}
+	if !balanced.Passed() {
+		t.Errorf("expected a balanced ledger with an open clearing account to pass")
+	}
+
+	tests := map[string]LedgerCheck{
+		"nonzero total":  {Totals: []CurrencyTotal{{Currency: "USD", Total: 0.01}}},
+		"unbalanced":     {UnbalancedCount: 1},
+		"mismatch":       {MismatchCount: 2},
+		"unposted entry": {UnpostedCount: 1},
+	}
+	for name, check := range tests {
+		if check.Passed() {
+			t.Errorf("%s: expected the check to fail", name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Wallet types seeded by the wallet_types migration.
+const (
+	TypeSavings    = "Savings"
+	TypeCreditCard = "Credit Card"
+	TypeCrypto     = "Crypto Wallet"
+)
+
+var (
+	ErrCreditLimitExceeded = errors.New("credit limit exceeded")
+	ErrWithdrawalCap       = errors.New("monthly withdrawal cap exceeded")
+	ErrPrecision           = errors.New("amount has too many decimal places for this wallet type")
+)
+
+// WithdrawalKinds are the debits a customer starts that count against a
+// monthly withdrawal cap: anything moving money out of the wallet, whether
+// to the outside world, another wallet or a savings goal. Fees, refunds and
+// adjustments are not the customer's doing and are never capped.
+var WithdrawalKinds = []string{KindWithdrawal, KindTransferOut, KindCapture, KindRoundUp}
+
+// Policy holds the balance rules for one wallet type. They are managed
+// through the wallet type admin endpoints, see Type.
+type Policy struct {
+	// AllowsNegative lets the balance go below zero, down to the wallet's
+	// credit limit.
+	AllowsNegative bool
+	// DefaultCreditLimit applies to wallets without their own limit.
+	DefaultCreditLimit float64
+	// MonthlyWithdrawalCap limits the total of WithdrawalKinds debits per
+	// calendar month; zero means no cap.
+	MonthlyWithdrawalCap float64
+	// Decimals is the number of decimal places amounts may have.
+	Decimals int
+}
+
+// Change is a proposed change to a wallet's balance.
+type Change struct {
+	Kind string
+	// Amount is signed: positive for credits, negative for debits.
+	Amount  float64
+	Balance float64
+	// CreditLimit is the wallet's own limit; zero falls back to the
+	// policy default.
+	CreditLimit float64
+	// WithdrawnThisMonth is the total of WithdrawalKinds debits already
+	// made this month.
+	WithdrawnThisMonth float64
+}
+
+// Check returns an error if the policy forbids ch.
+func (p Policy) Check(ch Change) error {
+	if !p.fits(ch.Amount) {
+		return fmt.Errorf("%w: at most %d", ErrPrecision, p.Decimals)
+	}
+	if ch.Amount >= 0 {
+		return nil
+	}
+
+	if p.capped(ch.Kind) && ch.WithdrawnThisMonth-ch.Amount > p.MonthlyWithdrawalCap {
+		return ErrWithdrawalCap
+	}
+
+	after := ch.Balance + ch.Amount
+	switch {
+	case after >= 0:
+		return nil
+	case !p.AllowsNegative, ch.Kind == KindRefundOut:
+		// Refunds only take back money the destination still has, never
+		// credit.
+		return ErrInsufficientFunds
+	case ch.Kind == KindFee:
+		// Fees are owed whether or not they fit under the credit limit.
+		return nil
+	case -after > p.CreditLimit(ch.CreditLimit):
+		return ErrCreditLimitExceeded
+	}
+	return nil
+}
+
+// CreditLimit returns the limit that applies to a wallet with the given
+// own limit.
+func (p Policy) CreditLimit(own float64) float64 {
+	if !p.AllowsNegative {
+		return 0
+	}
+	if own > 0 {
+		return own
+	}
+	return p.DefaultCreditLimit
+}
+
+// capped reports whether debits of kind count against the monthly
+// withdrawal cap. A hold is checked like the capture it may become, so that
+// it cannot reserve money that could never be captured.
+func (p Policy) capped(kind string) bool {
+	return p.MonthlyWithdrawalCap > 0 && (kind == KindHold || slices.Contains(WithdrawalKinds, kind))
+}
+
+// fits reports whether amount has no more than p.Decimals decimal places.
+func (p Policy) fits(amount float64) bool {
+	scaled := amount * math.Pow10(p.Decimals)
+	return math.Abs(scaled-math.Round(scaled)) < 1e-6
+}
@@ -0,0 +1,99 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	types := map[string]Type{
+		TypeSavings:    {Name: TypeSavings, Decimals: 2, MonthlyWithdrawalCap: 1000},
+		TypeCreditCard: {Name: TypeCreditCard, Decimals: 2, AllowsNegative: true, DefaultCreditLimit: 500},
+		TypeCrypto:     {Name: TypeCrypto, Decimals: 8},
+		"Gift Card":    {Name: "Gift Card"},
+	}
+
+	tests := []struct {
+		name       string
+		walletType string
+		change     Change
+		want       error
+	}{
+		{"savings withdrawal within balance", TypeSavings,
+			Change{Kind: KindWithdrawal, Amount: -100, Balance: 100}, nil},
+		{"savings overdraft", TypeSavings,
+			Change{Kind: KindWithdrawal, Amount: -100.01, Balance: 100}, ErrInsufficientFunds},
+		{"savings over monthly cap", TypeSavings,
+			Change{Kind: KindWithdrawal, Amount: -200, Balance: 5000, WithdrawnThisMonth: 900}, ErrWithdrawalCap},
+		{"savings transfer over monthly cap", TypeSavings,
+			Change{Kind: KindTransferOut, Amount: -200, Balance: 5000, WithdrawnThisMonth: 900}, ErrWithdrawalCap},
+		{"savings capture over monthly cap", TypeSavings,
+			Change{Kind: KindCapture, Amount: -200, Balance: 5000, WithdrawnThisMonth: 900}, ErrWithdrawalCap},
+		{"savings hold over monthly cap", TypeSavings,
+			Change{Kind: KindHold, Amount: -200, Balance: 5000, WithdrawnThisMonth: 900}, ErrWithdrawalCap},
+		{"savings round-up over monthly cap", TypeSavings,
+			Change{Kind: KindRoundUp, Amount: -0.5, Balance: 5000, WithdrawnThisMonth: 1000}, ErrWithdrawalCap},
+		{"savings transfer within monthly cap", TypeSavings,
+			Change{Kind: KindTransferOut, Amount: -100, Balance: 5000, WithdrawnThisMonth: 900}, nil},
+		{"savings fee ignores cap", TypeSavings,
+			Change{Kind: KindFee, Amount: -25, Balance: 5000, WithdrawnThisMonth: 1000}, nil},
+		{"savings adjustment ignores cap", TypeSavings,
+			Change{Kind: KindAdjustment, Amount: -200, Balance: 5000, WithdrawnThisMonth: 900}, nil},
+		{"credit card down to default limit", TypeCreditCard,
+			Change{Kind: KindWithdrawal, Amount: -600, Balance: 100}, nil},
+		{"credit card beyond default limit", TypeCreditCard,
+			Change{Kind: KindWithdrawal, Amount: -601, Balance: 100}, ErrCreditLimitExceeded},
+		{"credit card refund within balance", TypeCreditCard,
+			Change{Kind: KindRefundOut, Amount: -100, Balance: 100}, nil},
+		{"credit card refund into credit", TypeCreditCard,
+			Change{Kind: KindRefundOut, Amount: -100.01, Balance: 100}, ErrInsufficientFunds},
+		{"credit card fee beyond limit", TypeCreditCard,
+			Change{Kind: KindFee, Amount: -25, Balance: -490}, nil},
+		{"credit card within own limit", TypeCreditCard,
+			Change{Kind: KindWithdrawal, Amount: -2000, CreditLimit: 2000}, nil},
+		{"crypto eight decimals", TypeCrypto,
+			Change{Kind: KindDeposit, Amount: 0.00012345}, nil},
+		{"crypto nine decimals", TypeCrypto,
+			Change{Kind: KindDeposit, Amount: 0.000123456}, ErrPrecision},
+		{"savings three decimals", TypeSavings,
+			Change{Kind: KindDeposit, Amount: 1.005}, ErrPrecision},
+		{"whole units only", "Gift Card",
+			Change{Kind: KindDeposit, Amount: 0.5}, ErrPrecision},
+		{"type without overdraft cannot go negative", "Gift Card",
+			Change{Kind: KindWithdrawal, Amount: -1}, ErrInsufficientFunds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := types[tt.walletType].Policy().Check(tt.change)
+
+			if !errors.Is(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTypeValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  Type
+		want error
+	}{
+		{"valid", Type{Name: "Gift Card", Decimals: 2}, nil},
+		{"blank name", Type{Name: " ", Decimals: 2}, ErrInvalidWalletType},
+		{"too many decimals", Type{Name: "Gift Card", Decimals: 9}, ErrInvalidWalletType},
+		{"negative cap", Type{Name: "Gift Card", MonthlyWithdrawalCap: -1}, ErrInvalidWalletType},
+		{"credit limit without overdraft", Type{Name: "Gift Card", DefaultCreditLimit: 100}, ErrInvalidWalletType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.typ.Validate()
+
+			if !errors.Is(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrReportNotFound = errors.New("reconciliation report not found")
+
+// ReconciliationDescription is the description of adjustment entries
+// recorded by reconciliation.
+const ReconciliationDescription = "Reconciliation"
+
+// ReconciliationReport is the result of comparing every wallet's cached
+// balance with the sum of its ledger entries. With Fix, each discrepancy
+// was closed by an adjustment entry, or has the Error that prevented it;
+// the cached balance is never changed.
+type ReconciliationReport struct {
+	ID               int64         `json:"id" example:"12"`
+	WalletsChecked   int           `json:"wallets_checked" example:"250"`
+	DiscrepancyCount int           `json:"discrepancy_count" example:"1"`
+	Fix              bool          `json:"fix" example:"false"`
+	Discrepancies    []Discrepancy `json:"discrepancies,omitempty"`
+	CreatedAt        time.Time     `json:"created_at" example:"2024-04-01T02:00:00Z"`
+}
+
+// Discrepancy is a wallet whose cached balance differs from its ledger.
+type Discrepancy struct {
+	WalletID int     `json:"wallet_id" example:"1"`
+	Cached   float64 `json:"cached" example:"100.00"`
+	Ledger   float64 `json:"ledger" example:"90.00"`
+	// AdjustmentID is the entry that closed the discrepancy, if fixed.
+	AdjustmentID *int64 `json:"adjustment_id,omitempty" example:"431"`
+	// Error is why fixing the discrepancy failed.
+	Error string `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
+// FailedFixes returns the number of discrepancies that could not be fixed.
+func (r ReconciliationReport) FailedFixes() int {
+	n := 0
+	for _, d := range r.Discrepancies {
+		if d.Error != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+var ErrInvalidFix = errors.New("fix must be true or false")
+
+// ReconcileHandler
+//
+//	@Summary		Reconcile balances
+//	@Description	Compare every wallet's cached balance with the sum of its ledger entries now and record a report. With fix, each discrepancy is closed by an adjustment entry, one wallet at a time; a wallet whose fix fails is reported with the error and the others are still fixed. Cached balances are never changed.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			fix	query		bool	false	"Record adjustment entries for discrepancies"
+//	@Success		201	{object}	ReconciliationReport
+//	@Router			/api/v1/admin/reconciliation/reports [post]
+//	@Failure		400	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) ReconcileHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ReconcileHandler")
+	defer span.End()
+
+	fix := false
+	if v := c.QueryParam("fix"); v != "" {
+		var err error
+		if fix, err = strconv.ParseBool(v); err != nil {
+			return errorJSON(c, http.StatusBadRequest, ErrInvalidFix)
+		}
+	}
+	report, err := h.store.Reconcile(ctx, fix)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, report)
+}
+
+// ReconciliationReportsHandler
+//
+//	@Summary		List reconciliation reports
+//	@Description	List the latest reconciliation reports, newest first, without their discrepancies
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{array}		ReconciliationReport
+//	@Router			/api/v1/admin/reconciliation/reports [get]
+//	@Failure		500	{object}	Err
+func (h *Handler) ReconciliationReportsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ReconciliationReportsHandler")
+	defer span.End()
+
+	reports, err := h.store.ReconciliationReports(ctx)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, reports)
+}
+
+// ReconciliationReportHandler
+//
+//	@Summary		Get reconciliation report
+//	@Description	Get a reconciliation report with the wallets whose balance differed from their ledger
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"Report ID"
+//	@Success		200	{object}	ReconciliationReport
+//	@Router			/api/v1/admin/reconciliation/reports/{id} [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) ReconciliationReportHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ReconciliationReportHandler")
+	defer span.End()
+
+	report, err := h.store.ReconciliationReport(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,18 @@
+//go:build unit
+
+package wallet
+
+import "testing"
+
+func TestFailedFixes(t *testing.T) {
+	adjustment := int64(431)
+	r := ReconciliationReport{Fix: true, Discrepancies: []Discrepancy{
+		{WalletID: 1, AdjustmentID: &adjustment},
+		{WalletID: 2, Error: "context deadline exceeded"},
+		{WalletID: 3},
+	}}
+
+	if got := r.FailedFixes(); got != 1 {
+		t.Errorf("expected 1 failed fix but got %d", got)
+	}
+}
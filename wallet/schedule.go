@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+)
+
+// Scheduled transfer statuses. Only active schedules run.
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleCancelled = "cancelled"
+	ScheduleCompleted = "completed"
+)
+
+// Outcomes of a scheduled transfer run.
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+var (
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+	ErrInvalidSchedule           = errors.New("invalid schedule")
+	ErrScheduleState             = errors.New("scheduled transfer cannot be changed in its current status")
+)
+
+// Schedule yields the run times of a scheduled transfer.
+type Schedule interface {
+	// Next returns the first run strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a standard five-field cron expression such as
+// "0 9 1 * *", a descriptor such as "@monthly", or an RFC 5545 recurrence
+// rule such as "FREQ=MONTHLY;BYMONTHDAY=1", optionally prefixed with
+// "RRULE:". Recurrence rules are anchored at start. Times are in UTC.
+func ParseSchedule(expr string, start time.Time) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if rule, ok := strings.CutPrefix(expr, "RRULE:"); ok || strings.Contains(expr, "FREQ=") {
+		opt, err := rrule.StrToROption(rule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		opt.Dtstart = start.UTC()
+		r, err := rrule.NewRRule(*opt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		return rruleSchedule{r}, nil
+	}
+
+	s, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	return cronSchedule{s}, nil
+}
+
+type cronSchedule struct {
+	s cron.Schedule
+}
+
+func (c cronSchedule) Next(t time.Time) time.Time {
+	return c.s.Next(t.UTC())
+}
+
+type rruleSchedule struct {
+	r *rrule.RRule
+}
+
+func (r rruleSchedule) Next(t time.Time) time.Time {
+	return r.r.After(t.UTC(), false)
+}
+
+// ScheduledTransfer is a standing order that transfers Amount on every run
+// of Schedule between StartsAt and EndsAt.
+type ScheduledTransfer struct {
+	ID           int64      `json:"id" example:"1"`
+	FromWalletID int        `json:"from_wallet_id" example:"1"`
+	ToWalletID   int        `json:"to_wallet_id" example:"2"`
+	Amount       float64    `json:"amount" example:"500.00"`
+	Schedule     string     `json:"schedule" example:"0 0 1 * *"`
+	StartsAt     time.Time  `json:"starts_at" example:"2024-04-01T00:00:00Z"`
+	EndsAt       *time.Time `json:"ends_at,omitempty" example:"2025-04-01T00:00:00Z"`
+	Status       string     `json:"status" example:"active"`
+	// NextRunAt is the next run of the schedule; it is empty once the
+	// schedule has no more runs.
+	NextRunAt *time.Time `json:"next_run_at,omitempty" example:"2024-05-01T00:00:00Z"`
+	// RetryAt is when a failed run is tried again, and Attempts counts its
+	// failed tries so far.
+	RetryAt      *time.Time `json:"retry_at,omitempty" example:"2024-05-01T00:15:00Z"`
+	Attempts     int        `json:"attempts" example:"0"`
+	FailureCount int        `json:"failure_count" example:"0"`
+	LastError    string     `json:"last_error,omitempty" example:"insufficient funds"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty" example:"2024-04-01T00:00:05Z"`
+	CreatedAt    time.Time  `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+	UpdatedAt    time.Time  `json:"updated_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// ScheduledTransferRequest is the body of requests creating a scheduled
+// transfer. StartsAt defaults to now.
+type ScheduledTransferRequest struct {
+	FromWalletID int        `json:"from_wallet_id" example:"1"`
+	ToWalletID   int        `json:"to_wallet_id" example:"2"`
+	Amount       float64    `json:"amount" example:"500.00"`
+	Schedule     string     `json:"schedule" example:"FREQ=MONTHLY;BYMONTHDAY=1"`
+	StartsAt     time.Time  `json:"starts_at" example:"2024-04-01T00:00:00Z"`
+	EndsAt       *time.Time `json:"ends_at,omitempty" example:"2025-04-01T00:00:00Z"`
+}
+
+// FirstRun validates r and returns the time of its first run.
+func (r ScheduledTransferRequest) FirstRun() (time.Time, error) {
+	if err := (TransferRequest{FromWalletID: r.FromWalletID, ToWalletID: r.ToWalletID, Amount: r.Amount}).Validate(); err != nil {
+		return time.Time{}, err
+	}
+	s, err := ParseSchedule(r.Schedule, r.StartsAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// A run exactly at StartsAt counts.
+	first := s.Next(r.StartsAt.Add(-time.Second))
+	if first.IsZero() || r.EndsAt != nil && first.After(*r.EndsAt) {
+		return time.Time{}, fmt.Errorf("%w: no runs between starts_at and ends_at", ErrInvalidSchedule)
+	}
+	return first, nil
+}
+
+// ScheduledRun records one attempt to execute a scheduled transfer.
+type ScheduledRun struct {
+	ID                  int64     `json:"id" example:"1"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id" example:"1"`
+	ScheduledFor        time.Time `json:"scheduled_for" example:"2024-04-01T00:00:00Z"`
+	Attempt             int       `json:"attempt" example:"1"`
+	Status              string    `json:"status" example:"failed"`
+	Error               string    `json:"error,omitempty" example:"insufficient funds"`
+	TransferID          *int64    `json:"transfer_id,omitempty" example:"7"`
+	CreatedAt           time.Time `json:"created_at" example:"2024-04-01T00:00:05Z"`
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	after := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+		err  error
+	}{
+		{"cron", "0 9 1 * *", time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC), nil},
+		{"descriptor", "@monthly", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), nil},
+		{"rrule", "FREQ=MONTHLY;BYMONTHDAY=1", time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC), nil},
+		{"rrule with prefix", "RRULE:FREQ=WEEKLY;BYDAY=MO", time.Date(2024, 3, 25, 10, 0, 0, 0, time.UTC), nil},
+		{"invalid cron", "every day", time.Time{}, ErrInvalidSchedule},
+		{"invalid rrule", "FREQ=SOMETIMES", time.Time{}, ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr, start)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v but got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := s.Next(after); !got.Equal(tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFirstRun(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	r := ScheduledTransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: 500, Schedule: "@monthly", StartsAt: start}
+	got, err := r.FirstRun()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(start) {
+		t.Errorf("expected a run at the start %v but got %v", start, got)
+	}
+
+	r.StartsAt = start.Add(time.Hour)
+	r.EndsAt = &end
+	if _, err := r.FirstRun(); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("expected %v but got %v", ErrInvalidSchedule, err)
+	}
+}
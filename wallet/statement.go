@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+var ErrStatementNotFound = errors.New("statement not found")
+
+// Statement summarises a credit card wallet's ledger over one billing
+// period. Balances are signed like the ledger, so an amount owed is a
+// negative closing balance.
+type Statement struct {
+	ID             int64     `json:"id" example:"1"`
+	WalletID       int       `json:"wallet_id" example:"1"`
+	PeriodStart    time.Time `json:"period_start" example:"2024-03-01T00:00:00Z"`
+	PeriodEnd      time.Time `json:"period_end" example:"2024-04-01T00:00:00Z"`
+	OpeningBalance float64   `json:"opening_balance" example:"0.00"`
+	ClosingBalance float64   `json:"closing_balance" example:"-500.00"`
+	TotalCredits   float64   `json:"total_credits" example:"100.00"`
+	TotalDebits    float64   `json:"total_debits" example:"600.00"`
+	MinimumPayment float64   `json:"minimum_payment" example:"25.00"`
+	DueDate        time.Time `json:"due_date" example:"2024-04-22T00:00:00Z"`
+	// LateFee is the fee posted when less than the minimum payment arrived
+	// by the due date.
+	LateFee   float64   `json:"late_fee,omitempty" example:"25.00"`
+	CreatedAt time.Time `json:"created_at" example:"2024-04-01T00:05:00Z"`
+	// Transactions are the ledger entries of the period; they are only
+	// included when a single statement is requested.
+	Transactions []Transaction `json:"transactions,omitempty"`
+}
+
+// StatementPeriodEnd returns the most recent statement closing time at or
+// before now: midnight UTC on the configured statement day.
+func StatementPeriodEnd(now time.Time, cfg config.Billing) time.Time {
+	now = now.UTC()
+	end := time.Date(now.Year(), now.Month(), cfg.StatementDay, 0, 0, 0, 0, time.UTC)
+	if end.After(now) {
+		end = end.AddDate(0, -1, 0)
+	}
+	return end
+}
+
+// DueDate returns the payment due date of a statement closing at end.
+func DueDate(end time.Time, cfg config.Billing) time.Time {
+	return end.AddDate(0, 0, cfg.PaymentDueDays)
+}
+
+// MinimumPayment returns the minimum payment due on a statement with the
+// given closing balance. Nothing is due unless the balance is negative.
+func MinimumPayment(closing float64, cfg config.Billing) float64 {
+	owed := -closing
+	if owed <= 0 {
+		return 0
+	}
+	due := math.Max(cfg.MinimumPayment, math.Round(owed*cfg.MinimumPaymentRate*100)/100)
+	return math.Min(owed, due)
+}
+
+// AmountPaid returns how much of entries counts towards paying a
+// statement: every credit, whether a deposit, a transfer such as a
+// standing order from another wallet, or a refund, except interest and
+// fees the bank itself posted.
+func AmountPaid(entries []Transaction) float64 {
+	var paid float64
+	for _, t := range entries {
+		if t.Amount > 0 && t.Kind != KindInterest && t.Kind != KindFee {
+			paid += t.Amount
+		}
+	}
+	return paid
+}
@@ -0,0 +1,83 @@
+//go:build unit
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openmymai/fun-exercise-api/config"
+)
+
+func TestStatementPeriodEnd(t *testing.T) {
+	cfg := config.Billing{StatementDay: 15, PaymentDueDays: 21}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"after statement day", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"on statement day", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"before statement day", time.Date(2024, 3, 14, 23, 0, 0, 0, time.UTC), time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{"across new year", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StatementPeriodEnd(tt.now, cfg)
+
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+
+	due := DueDate(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), cfg)
+	if want := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC); !due.Equal(want) {
+		t.Errorf("expected due date %v but got %v", want, due)
+	}
+}
+
+func TestMinimumPayment(t *testing.T) {
+	cfg := config.Billing{MinimumPaymentRate: 0.02, MinimumPayment: 25}
+
+	tests := []struct {
+		name    string
+		closing float64
+		want    float64
+	}{
+		{"nothing owed", 100, 0},
+		{"floor applies", -500, 25},
+		{"rate applies", -5000, 100},
+		{"never more than owed", -10, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MinimumPayment(tt.closing, cfg)
+
+			if got != tt.want {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAmountPaid(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Transaction
+		want    float64
+	}{
+		{"deposit", []Transaction{{Kind: KindDeposit, Amount: 50}}, 50},
+		{"repayment by transfer", []Transaction{{Kind: KindTransferIn, Amount: 25}, {Kind: KindTransferIn, Amount: 10}}, 35},
+		{"refund", []Transaction{{Kind: KindRefundIn, Amount: 30}}, 30},
+		{"interest and debits do not count", []Transaction{{Kind: KindInterest, Amount: 2}, {Kind: KindWithdrawal, Amount: -40}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AmountPaid(tt.entries); got != tt.want {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
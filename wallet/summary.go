@@ -0,0 +1,39 @@
+package wallet
+
+import "time"
+
+// UserSummary is a user's portfolio across their wallets. Amounts in
+// different currencies are never added together, so net worth is reported
+// per currency.
+type UserSummary struct {
+	UserID      int               `json:"user_id" example:"1"`
+	WalletCount int               `json:"wallet_count" example:"3"`
+	Currencies  []CurrencySummary `json:"currencies"`
+	WalletTypes []TypeSummary     `json:"wallet_types"`
+	// MonthStart is when the month-over-month change is measured from.
+	MonthStart time.Time `json:"month_start" example:"2024-03-01T00:00:00Z"`
+}
+
+// CurrencySummary totals a user's wallets in one currency.
+type CurrencySummary struct {
+	Currency    string  `json:"currency" example:"USD"`
+	WalletCount int     `json:"wallet_count" example:"3"`
+	Assets      float64 `json:"assets" example:"5000.00"`
+	// Debt is what negative balances, such as credit cards, owe.
+	Debt     float64 `json:"debt" example:"800.00"`
+	NetWorth float64 `json:"net_worth" example:"4200.00"`
+	// MonthStartNetWorth is the net worth at MonthStart according to the
+	// ledger.
+	MonthStartNetWorth float64 `json:"month_start_net_worth" example:"3900.00"`
+	MonthChange        float64 `json:"month_change" example:"300.00"`
+	// MonthChangePercent is omitted when the month started at zero.
+	MonthChangePercent *float64 `json:"month_change_percent,omitempty" example:"7.7"`
+}
+
+// TypeSummary totals a user's wallets of one type and currency.
+type TypeSummary struct {
+	WalletType string  `json:"wallet_type" example:"Savings"`
+	Currency   string  `json:"currency" example:"USD"`
+	Count      int     `json:"count" example:"2"`
+	Balance    float64 `json:"balance" example:"5000.00"`
+}
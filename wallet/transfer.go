@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Ledger kinds of the two sides of a transfer, and of a refund moving money
+// back from the transfer's destination to its source.
+const (
+	KindTransferOut = "transfer_out"
+	KindTransferIn  = "transfer_in"
+	KindRefundOut   = "refund_out"
+	KindRefundIn    = "refund_in"
+)
+
+// Transfer statuses.
+const (
+	TransferCompleted         = "completed"
+	TransferPartiallyRefunded = "partially_refunded"
+	TransferRefunded          = "refunded"
+	TransferReversed          = "reversed"
+)
+
+var (
+	ErrTransferNotFound    = errors.New("transfer not found")
+	ErrSameWallet          = errors.New("cannot transfer to the same wallet")
+	ErrInvalidReason       = errors.New("reason must be at most 500 characters")
+	ErrTransferRefunded    = errors.New("transfer has already been refunded in full")
+	ErrRefundExceedsAmount = errors.New("refunds would exceed the transfer amount")
+)
+
+// Transfer moves money from one wallet to another as a debit and a credit
+// recorded in one database transaction.
+type Transfer struct {
+	ID                  int64   `json:"id" example:"1"`
+	FromWalletID        int     `json:"from_wallet_id" example:"1"`
+	ToWalletID          int     `json:"to_wallet_id" example:"2"`
+	Amount              float64 `json:"amount" example:"500.00"`
+	DebitTransactionID  int64   `json:"debit_transaction_id" example:"10"`
+	CreditTransactionID int64   `json:"credit_transaction_id" example:"11"`
+	ScheduledTransferID *int64  `json:"scheduled_transfer_id,omitempty" example:"3"`
+	Status              string  `json:"status" example:"partially_refunded"`
+	RefundedAmount      float64 `json:"refunded_amount" example:"100.00"`
+	// ReversedAt is set once the transfer has been reversed.
+	ReversedAt *time.Time       `json:"reversed_at,omitempty" example:"2024-03-26T09:00:00Z"`
+	Refunds    []TransferRefund `json:"refunds,omitempty"`
+	CreatedAt  time.Time        `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// TransferStatus derives a transfer's status from its refunds.
+func TransferStatus(amount, refunded float64, reversed bool) string {
+	switch {
+	case reversed:
+		return TransferReversed
+	case refunded >= amount:
+		return TransferRefunded
+	case refunded > 0:
+		return TransferPartiallyRefunded
+	}
+	return TransferCompleted
+}
+
+// TransferRefund moves part or all of a transfer back from its destination
+// to its source as a debit and a credit linked to the transfer. A reversal
+// refunds whatever has not been refunded yet.
+type TransferRefund struct {
+	ID                  int64     `json:"id" example:"1"`
+	TransferID          int64     `json:"transfer_id" example:"1"`
+	Amount              float64   `json:"amount" example:"100.00"`
+	Reversal            bool      `json:"reversal" example:"false"`
+	Reason              string    `json:"reason,omitempty" example:"Duplicate payment"`
+	DebitTransactionID  int64     `json:"debit_transaction_id" example:"12"`
+	CreditTransactionID int64     `json:"credit_transaction_id" example:"13"`
+	CreatedAt           time.Time `json:"created_at" example:"2024-03-26T09:00:00Z"`
+}
+
+// RefundRequest is the body of refund and reversal requests. Amount is
+// ignored by reversals.
+type RefundRequest struct {
+	Amount float64 `json:"amount,omitempty" example:"100.00"`
+	Reason string  `json:"reason,omitempty" example:"Duplicate payment"`
+}
+
+// Validate normalises r and reports the first problem with it. Amount is
+// only checked for partial refunds.
+func (r *RefundRequest) Validate(reversal bool) error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	switch {
+	case !reversal && r.Amount <= 0:
+		return ErrInvalidAmount
+	case len(r.Reason) > 500:
+		return ErrInvalidReason
+	}
+	return nil
+}
+
+// RefundDescription describes the ledger entries of a refund.
+func RefundDescription(transferID int64, reversal bool) string {
+	if reversal {
+		return fmt.Sprintf("Reversal of transfer %d", transferID)
+	}
+	return fmt.Sprintf("Refund of transfer %d", transferID)
+}
+
+// TransferRequest is the body of requests creating a transfer.
+type TransferRequest struct {
+	FromWalletID int     `json:"from_wallet_id" example:"1"`
+	ToWalletID   int     `json:"to_wallet_id" example:"2"`
+	Amount       float64 `json:"amount" example:"500.00"`
+}
+
+// Validate reports the first problem with r.
+func (r TransferRequest) Validate() error {
+	switch {
+	case r.Amount <= 0:
+		return ErrInvalidAmount
+	case r.FromWalletID == r.ToWalletID:
+		return ErrSameWallet
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package wallet
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/tracing"
+)
+
+// CreateTransferHandler
+//
+//	@Summary		Create transfer
+//	@Description	Move money from one wallet to another. The debit is subject to the source wallet's type rules and holds.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		TransferRequest	true	"Transfer"
+//	@Success		201		{object}	Transfer
+//	@Router			/api/v1/transfers [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateTransferHandler")
+	defer span.End()
+
+	req := TransferRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	t, err := h.store.CreateTransfer(ctx, req)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, t)
+}
+
+// TransferHandler
+//
+//	@Summary		Get transfer
+//	@Description	Get a transfer with its refunds and reversal
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Transfer ID"
+//	@Success		200	{object}	Transfer
+//	@Router			/api/v1/transfers/{id} [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) TransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.TransferHandler")
+	defer span.End()
+
+	t, err := h.store.Transfer(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// ReverseTransferHandler
+//
+//	@Summary		Reverse transfer
+//	@Description	Move whatever has not been refunded yet back from the destination wallet to the source wallet as entries linked to the transfer. The destination must have the funds available; its credit limit is not used.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Transfer ID"
+//	@Param			body	body		RefundRequest	false	"Reason; the amount is ignored"
+//	@Success		200		{object}	Transfer
+//	@Router			/api/v1/transfers/{id}/reverse [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) ReverseTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ReverseTransferHandler")
+	defer span.End()
+
+	req := RefundRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := req.Validate(true); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	t, err := h.store.ReverseTransfer(ctx, c.Param("id"), req)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+// RefundTransferHandler
+//
+//	@Summary		Refund transfer
+//	@Description	Move part of a transfer back from the destination wallet to the source wallet as entries linked to the transfer. Refunds may be repeated until they add up to the transfer amount. The destination must have the funds available; its credit limit is not used.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Transfer ID"
+//	@Param			body	body		RefundRequest	true	"Refund"
+//	@Success		201		{object}	Transfer
+//	@Router			/api/v1/transfers/{id}/refunds [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		422		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) RefundTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.RefundTransferHandler")
+	defer span.End()
+
+	req := RefundRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+	if err := req.Validate(false); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	t, err := h.store.RefundTransfer(ctx, c.Param("id"), req)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, t)
+}
+
+// CreateScheduledTransferHandler
+//
+//	@Summary		Create scheduled transfer
+//	@Description	Create a standing order. The schedule is a five-field cron expression ("0 0 1 * *"), a descriptor ("@monthly") or a recurrence rule ("FREQ=MONTHLY;BYMONTHDAY=1"), in UTC.
+//	@Tags			transfer
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		ScheduledTransferRequest	true	"Scheduled transfer"
+//	@Success		201		{object}	ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers [post]
+//	@Failure		400		{object}	Err
+//	@Failure		404		{object}	Err
+//	@Failure		500		{object}	Err
+func (h *Handler) CreateScheduledTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CreateScheduledTransferHandler")
+	defer span.End()
+
+	req := ScheduledTransferRequest{}
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err)
+	}
+
+	s, err := h.store.CreateScheduledTransfer(ctx, req)
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, s)
+}
+
+// ScheduledTransfersHandler
+//
+//	@Summary		List scheduled transfers
+//	@Description	List scheduled transfers, optionally only those from or to a wallet
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			wallet_id	query		int	false	"Wallet ID"
+//	@Success		200			{array}		ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers [get]
+//	@Failure		404			{object}	Err
+//	@Failure		500			{object}	Err
+func (h *Handler) ScheduledTransfersHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ScheduledTransfersHandler")
+	defer span.End()
+
+	transfers, err := h.store.ScheduledTransfers(ctx, c.QueryParam("wallet_id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, transfers)
+}
+
+// ScheduledTransferHandler
+//
+//	@Summary		Get scheduled transfer
+//	@Description	Get a scheduled transfer with its next run and failure state
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Scheduled transfer ID"
+//	@Success		200	{object}	ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers/{id} [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) ScheduledTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ScheduledTransferHandler")
+	defer span.End()
+
+	s, err := h.store.ScheduledTransfer(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, s)
+}
+
+// ScheduledRunsHandler
+//
+//	@Summary		List scheduled transfer runs
+//	@Description	List every attempt to execute a scheduled transfer, newest first
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Scheduled transfer ID"
+//	@Success		200	{array}		ScheduledRun
+//	@Router			/api/v1/scheduled-transfers/{id}/runs [get]
+//	@Failure		404	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) ScheduledRunsHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ScheduledRunsHandler")
+	defer span.End()
+
+	runs, err := h.store.ScheduledRuns(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+// PauseScheduledTransferHandler
+//
+//	@Summary		Pause scheduled transfer
+//	@Description	Stop an active scheduled transfer from running until it is resumed
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Scheduled transfer ID"
+//	@Success		200	{object}	ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers/{id}/pause [post]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) PauseScheduledTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.PauseScheduledTransferHandler")
+	defer span.End()
+
+	s, err := h.store.PauseScheduledTransfer(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, s)
+}
+
+// ResumeScheduledTransferHandler
+//
+//	@Summary		Resume scheduled transfer
+//	@Description	Resume a paused scheduled transfer from its next run; runs missed while paused are skipped
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Scheduled transfer ID"
+//	@Success		200	{object}	ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers/{id}/resume [post]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) ResumeScheduledTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.ResumeScheduledTransferHandler")
+	defer span.End()
+
+	s, err := h.store.ResumeScheduledTransfer(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, s)
+}
+
+// CancelScheduledTransferHandler
+//
+//	@Summary		Cancel scheduled transfer
+//	@Description	Cancel an active or paused scheduled transfer for good
+//	@Tags			transfer
+//	@Produce		json
+//	@Param			id	path		int	true	"Scheduled transfer ID"
+//	@Success		200	{object}	ScheduledTransfer
+//	@Router			/api/v1/scheduled-transfers/{id}/cancel [post]
+//	@Failure		404	{object}	Err
+//	@Failure		422	{object}	Err
+//	@Failure		500	{object}	Err
+func (h *Handler) CancelScheduledTransferHandler(c echo.Context) error {
+	ctx, span := tracing.Start(c.Request().Context(), "wallet.Handler.CancelScheduledTransferHandler")
+	defer span.End()
+
+	s, err := h.store.CancelScheduledTransfer(ctx, c.Param("id"))
+	if err != nil {
+		return storeError(c, err)
+	}
+	return c.JSON(http.StatusOK, s)
+}
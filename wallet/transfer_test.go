@@ -0,0 +1,46 @@
+//go:build unit
+
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTransferStatus(t *testing.T) {
+	tests := []struct {
+		refunded float64
+		reversed bool
+		want     string
+	}{
+		{0, false, TransferCompleted},
+		{100, false, TransferPartiallyRefunded},
+		{500, false, TransferRefunded},
+		{500, true, TransferReversed},
+	}
+	for _, tt := range tests {
+		if got := TransferStatus(500, tt.refunded, tt.reversed); got != tt.want {
+			t.Errorf("TransferStatus(500, %v, %v): expected %q but got %q", tt.refunded, tt.reversed, tt.want, got)
+		}
+	}
+}
+
+func TestRefundRequestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      RefundRequest
+		reversal bool
+		want     error
+	}{
+		{"partial refund", RefundRequest{Amount: 100}, false, nil},
+		{"refund without amount", RefundRequest{}, false, ErrInvalidAmount},
+		{"reversal without amount", RefundRequest{Reason: " Sent to the wrong wallet "}, true, nil},
+		{"long reason", RefundRequest{Amount: 100, Reason: strings.Repeat("x", 501)}, false, ErrInvalidReason},
+	}
+	for _, tt := range tests {
+		if err := tt.req.Validate(tt.reversal); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v but got %v", tt.name, tt.want, err)
+		}
+	}
+}
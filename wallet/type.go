@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTypeNotFound      = errors.New("wallet type not found")
+	ErrTypeExists        = errors.New("wallet type already exists")
+	ErrTypeRetired       = errors.New("wallet type is retired")
+	ErrInvalidWalletType = errors.New("invalid wallet type")
+	// ErrUnknownWalletType is returned when a wallet names a type that
+	// does not exist.
+	ErrUnknownWalletType = errors.New("unknown wallet type")
+)
+
+// Type is a wallet type together with the balance rules its wallets follow.
+// Retired types keep their existing wallets but cannot be used for new ones.
+// InterestSince is when the type last started earning interest; no interest
+// accrues for days before it.
+type Type struct {
+	Name                 string     `json:"name" example:"Savings"`
+	AllowsNegative       bool       `json:"allows_negative" example:"false"`
+	Decimals             int        `json:"decimals" example:"2"`
+	DefaultCreditLimit   float64    `json:"default_credit_limit" example:"0"`
+	MonthlyWithdrawalCap float64    `json:"monthly_withdrawal_cap" example:"50000.00"`
+	EarnsInterest        bool       `json:"earns_interest" example:"true"`
+	InterestSince        *time.Time `json:"interest_since,omitempty" example:"2024-03-25T14:19:00.729237Z"`
+	HoldsAssets          bool       `json:"holds_assets" example:"false"`
+	AllowsGoals          bool       `json:"allows_goals" example:"true"`
+	RetiredAt            *time.Time `json:"retired_at,omitempty" example:"2024-03-25T14:19:00.729237Z"`
+	CreatedAt            time.Time  `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// maxDecimals matches the scale of the balance columns.
+const maxDecimals = 8
+
+// Validate reports the first problem with t's attributes.
+func (t Type) Validate() error {
+	switch {
+	case strings.TrimSpace(t.Name) == "" || len(t.Name) > 64:
+		return errors.Join(ErrInvalidWalletType, errors.New("name must be 1 to 64 characters"))
+	case t.Decimals < 0 || t.Decimals > maxDecimals:
+		return errors.Join(ErrInvalidWalletType, errors.New("decimals must be between 0 and 8"))
+	case t.DefaultCreditLimit < 0 || t.MonthlyWithdrawalCap < 0:
+		return errors.Join(ErrInvalidWalletType, errors.New("limits must not be negative"))
+	case t.DefaultCreditLimit > 0 && !t.AllowsNegative:
+		return errors.Join(ErrInvalidWalletType, errors.New("default_credit_limit requires allows_negative"))
+	}
+	return nil
+}
+
+// Policy returns the balance rules of wallets of type t.
+func (t Type) Policy() Policy {
+	return Policy{
+		AllowsNegative:       t.AllowsNegative,
+		DefaultCreditLimit:   t.DefaultCreditLimit,
+		MonthlyWithdrawalCap: t.MonthlyWithdrawalCap,
+		Decimals:             t.Decimals,
+	}
+}
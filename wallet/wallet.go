@@ -1,13 +1,131 @@
 package wallet
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("wallet not found")
+
+// DefaultCurrency is the currency of wallets created without one.
+const DefaultCurrency = "USD"
+
+var (
+	ErrInvalidCurrency  = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrCurrencyChange   = errors.New("wallet currency cannot be changed")
+	ErrCurrencyMismatch = errors.New("wallets have different currencies")
+)
+
+// NormalizeCurrency upper-cases a currency code, defaulting an empty one to
+// DefaultCurrency.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", ErrInvalidCurrency
+	}
+	return currency, nil
+}
 
 type Wallet struct {
-	ID         int       `json:"id" example:"1"`
-	UserID     int       `json:"user_id" example:"1"`
-	UserName   string    `json:"user_name" example:"John Doe"`
-	WalletName string    `json:"wallet_name" example:"John's Wallet"`
-	WalletType string    `json:"wallet_type" example:"Credit Card"`
-	Balance    float64   `json:"balance" example:"100.00"`
-	CreatedAt  time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+	ID         int    `json:"id" example:"1"`
+	UserID     int    `json:"user_id" example:"1"`
+	UserName   string `json:"user_name" example:"John Doe"`
+	WalletName string `json:"wallet_name" example:"John's Wallet"`
+	WalletType string `json:"wallet_type" example:"Credit Card"`
+	// Currency is the ISO 4217 code of the balance. It defaults to
+	// DefaultCurrency and cannot change once the wallet exists.
+	Currency string  `json:"currency" example:"USD"`
+	Balance  float64 `json:"balance" example:"100.00"`
+	// AvailableBalance is the balance less active holds; debits are
+	// checked against it.
+	AvailableBalance float64 `json:"available_balance" example:"60.00"`
+	// CreditLimit is how far below zero the wallet may go if its type
+	// allows negative balances.
+	CreditLimit float64   `json:"credit_limit,omitempty" example:"1000.00"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// TypeTotal aggregates the wallets of a single wallet type.
+type TypeTotal struct {
+	WalletType string  `json:"wallet_type" example:"Savings"`
+	Count      int     `json:"count" example:"2"`
+	Balance    float64 `json:"balance" example:"3000.00"`
+}
+
+var (
+	ErrInvalidAmount     = errors.New("amount must be greater than zero")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidMetadata   = errors.New("merchant must be at most 100 and description at most 500 characters")
+)
+
+// Transaction kinds recorded in the wallet ledger.
+const (
+	KindOpening    = "opening"
+	KindAdjustment = "adjustment"
+	KindDeposit    = "deposit"
+	KindWithdrawal = "withdrawal"
+	KindFee        = "fee"
+)
+
+// Transaction is a single ledger entry. Amount is signed: positive for
+// credits and negative for debits.
+type Transaction struct {
+	ID           int64   `json:"id" example:"1"`
+	WalletID     int     `json:"wallet_id" example:"1"`
+	Kind         string  `json:"kind" example:"deposit"`
+	Amount       float64 `json:"amount" example:"50.00"`
+	BalanceAfter float64 `json:"balance_after" example:"150.00"`
+	// Category classifies the entry for budgets and reports, e.g.
+	// "groceries".
+	Category    string    `json:"category,omitempty" example:"groceries"`
+	Merchant    string    `json:"merchant,omitempty" example:"Tesco"`
+	Description string    `json:"description,omitempty" example:"Weekly shop"`
+	Tags        []string  `json:"tags,omitempty" example:"family"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-25T14:19:00.729237Z"`
+}
+
+// AmountRequest is the body of deposit and withdrawal requests. Without a
+// category, one is picked by the category rules from the merchant or
+// description.
+type AmountRequest struct {
+	Amount      float64  `json:"amount" example:"50.00"`
+	Category    string   `json:"category,omitempty" example:"groceries"`
+	Merchant    string   `json:"merchant,omitempty" example:"Tesco"`
+	Description string   `json:"description,omitempty" example:"Weekly shop"`
+	Tags        []string `json:"tags,omitempty" example:"family"`
+}
+
+// Validate normalises r's metadata and reports the first problem with it.
+func (r *AmountRequest) Validate() error {
+	if r.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	r.Category = strings.ToLower(strings.TrimSpace(r.Category))
+	r.Merchant = strings.TrimSpace(r.Merchant)
+	r.Description = strings.TrimSpace(r.Description)
+	if len(r.Merchant) > 100 || len(r.Description) > 500 {
+		return ErrInvalidMetadata
+	}
+	tags, err := NormalizeTags(r.Tags)
+	if err != nil {
+		return err
+	}
+	r.Tags = tags
+	return nil
+}
+
+// Transaction returns the ledger entry r describes, of kind and with the
+// signed amount.
+func (r AmountRequest) Transaction(kind string, amount float64) Transaction {
+	return Transaction{Kind: kind, Amount: amount, Category: r.Category, Merchant: r.Merchant, Description: r.Description, Tags: r.Tags}
+}
+
+// MovementResult is returned after a deposit or withdrawal.
+type MovementResult struct {
+	Balance     float64     `json:"balance" example:"150.00"`
+	Transaction Transaction `json:"transaction"`
 }
@@ -3,13 +3,17 @@
 package wallet
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/openmymai/fun-exercise-api/logging"
 )
 
 type StubWallet struct {
@@ -18,33 +22,304 @@ type StubWallet struct {
 	walletsQuery  []Wallet
 	createWallet  Wallet
 	updateWallet  Wallet
+	transaction   Transaction
+	statements    []Statement
+	statement     Statement
+	interest      InterestPreview
+	holdings      []Holding
+	holding       Holding
+	valuation     Valuation
+	holds         []Hold
+	hold          Hold
+	transfer      Transfer
+	scheduled     []ScheduledTransfer
+	runs          []ScheduledRun
+	budgets       []BudgetStatus
+	budget        Budget
+	alerts        []BudgetAlert
+	goals         []GoalProgress
+	goal          Goal
+	categories    []Category
+	category      Category
+	rules         []CategoryRule
+	rule          CategoryRule
+	spending      SpendingReport
+	summary       UserSummary
+	balance       Balance
+	series        BalanceSeries
+	check         LedgerCheck
+	accounts      []Account
+	entry         JournalEntry
+	report        ReconciliationReport
+	reports       []ReconciliationReport
+	walletTypes   []Type
+	walletType    Type
 	err           error
 }
 
-func (s StubWallet) Wallets() ([]Wallet, error) {
+func (s StubWallet) Wallets(ctx context.Context) ([]Wallet, error) {
 	return s.wallets, s.err
 }
 
-func (s StubWallet) WalletsQuery(id string) ([]Wallet, error) {
+func (s StubWallet) WalletsQuery(ctx context.Context, id string) ([]Wallet, error) {
 	return s.walletsQuery, s.err
 }
 
-func (s StubWallet) WalletsByUser(id string) ([]Wallet, error) {
+func (s StubWallet) WalletsByUser(ctx context.Context, id string) ([]Wallet, error) {
 	return s.walletsByUser, s.err
 }
 
-func (s StubWallet) CreateWallet(wallet Wallet) (Wallet, error) {
+func (s StubWallet) CreateWallet(ctx context.Context, wallet Wallet) (Wallet, error) {
 	return s.createWallet, s.err
 }
 
-func (s StubWallet) UpdateWallet(wallet Wallet, id string) (Wallet, error) {
+func (s StubWallet) UpdateWallet(ctx context.Context, wallet Wallet, id string) (Wallet, error) {
 	return s.updateWallet, s.err
 }
 
-func (s StubWallet) DeleteWallet(id string) error {
+func (s StubWallet) DeleteWallet(ctx context.Context, id string) error {
 	return s.err
 }
 
+func (s StubWallet) UserSummary(ctx context.Context, userID string, now time.Time) (UserSummary, error) {
+	return s.summary, s.err
+}
+
+func (s StubWallet) BalanceAt(ctx context.Context, walletID string, asOf time.Time) (Balance, error) {
+	return s.balance, s.err
+}
+
+func (s StubWallet) BalanceSeries(ctx context.Context, walletID, granularity string, from, to time.Time) (BalanceSeries, error) {
+	return s.series, s.err
+}
+
+func (s StubWallet) Deposit(ctx context.Context, id string, r AmountRequest) (Transaction, error) {
+	return s.transaction, s.err
+}
+
+func (s StubWallet) Withdraw(ctx context.Context, id string, r AmountRequest) (Transaction, error) {
+	return s.transaction, s.err
+}
+
+func (s StubWallet) Statements(ctx context.Context, walletID string) ([]Statement, error) {
+	return s.statements, s.err
+}
+
+func (s StubWallet) Statement(ctx context.Context, walletID, statementID string) (Statement, error) {
+	return s.statement, s.err
+}
+
+func (s StubWallet) InterestPreview(ctx context.Context, walletID string, now time.Time) (InterestPreview, error) {
+	return s.interest, s.err
+}
+
+func (s StubWallet) Holdings(ctx context.Context, walletID string) ([]Holding, error) {
+	return s.holdings, s.err
+}
+
+func (s StubWallet) AddHolding(ctx context.Context, walletID, asset, quantity string) (Holding, error) {
+	return s.holding, s.err
+}
+
+func (s StubWallet) RemoveHolding(ctx context.Context, walletID, asset, quantity string) (Holding, error) {
+	return s.holding, s.err
+}
+
+func (s StubWallet) Valuation(ctx context.Context, walletID, currency string) (Valuation, error) {
+	return s.valuation, s.err
+}
+
+func (s StubWallet) Holds(ctx context.Context, walletID string) ([]Hold, error) {
+	return s.holds, s.err
+}
+
+func (s StubWallet) PlaceHold(ctx context.Context, walletID string, amount float64, expiresAt time.Time) (Hold, error) {
+	return s.hold, s.err
+}
+
+func (s StubWallet) CaptureHold(ctx context.Context, walletID, holdID string, amount float64) (Hold, error) {
+	return s.hold, s.err
+}
+
+func (s StubWallet) VoidHold(ctx context.Context, walletID, holdID string) (Hold, error) {
+	return s.hold, s.err
+}
+
+func (s StubWallet) CreateTransfer(ctx context.Context, r TransferRequest) (Transfer, error) {
+	return s.transfer, s.err
+}
+
+func (s StubWallet) Transfer(ctx context.Context, id string) (Transfer, error) {
+	return s.transfer, s.err
+}
+
+func (s StubWallet) RefundTransfer(ctx context.Context, id string, r RefundRequest) (Transfer, error) {
+	return s.transfer, s.err
+}
+
+func (s StubWallet) ReverseTransfer(ctx context.Context, id string, r RefundRequest) (Transfer, error) {
+	return s.transfer, s.err
+}
+
+func (s StubWallet) CreateScheduledTransfer(ctx context.Context, r ScheduledTransferRequest) (ScheduledTransfer, error) {
+	return s.scheduledTransfer(), s.err
+}
+
+func (s StubWallet) ScheduledTransfers(ctx context.Context, walletID string) ([]ScheduledTransfer, error) {
+	return s.scheduled, s.err
+}
+
+func (s StubWallet) ScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error) {
+	return s.scheduledTransfer(), s.err
+}
+
+func (s StubWallet) ScheduledRuns(ctx context.Context, id string) ([]ScheduledRun, error) {
+	return s.runs, s.err
+}
+
+func (s StubWallet) PauseScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error) {
+	return s.scheduledTransfer(), s.err
+}
+
+func (s StubWallet) ResumeScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error) {
+	return s.scheduledTransfer(), s.err
+}
+
+func (s StubWallet) CancelScheduledTransfer(ctx context.Context, id string) (ScheduledTransfer, error) {
+	return s.scheduledTransfer(), s.err
+}
+
+func (s StubWallet) scheduledTransfer() ScheduledTransfer {
+	if len(s.scheduled) == 0 {
+		return ScheduledTransfer{}
+	}
+	return s.scheduled[0]
+}
+
+func (s StubWallet) WalletBudgets(ctx context.Context, walletID string, now time.Time) ([]BudgetStatus, error) {
+	return s.budgets, s.err
+}
+
+func (s StubWallet) UserBudgets(ctx context.Context, userID string, now time.Time) ([]BudgetStatus, error) {
+	return s.budgets, s.err
+}
+
+func (s StubWallet) Budget(ctx context.Context, id string, now time.Time) (BudgetStatus, error) {
+	if len(s.budgets) == 0 {
+		return BudgetStatus{}, s.err
+	}
+	return s.budgets[0], s.err
+}
+
+func (s StubWallet) CreateBudget(ctx context.Context, b Budget) (Budget, error) {
+	return s.budget, s.err
+}
+
+func (s StubWallet) DeleteBudget(ctx context.Context, id string) (Budget, error) {
+	return s.budget, s.err
+}
+
+func (s StubWallet) BudgetAlerts(ctx context.Context, id string) ([]BudgetAlert, error) {
+	return s.alerts, s.err
+}
+
+func (s StubWallet) Goals(ctx context.Context, walletID string, now time.Time) ([]GoalProgress, error) {
+	return s.goals, s.err
+}
+
+func (s StubWallet) Goal(ctx context.Context, walletID, goalID string, now time.Time) (GoalProgress, error) {
+	if len(s.goals) == 0 {
+		return GoalProgress{}, s.err
+	}
+	return s.goals[0], s.err
+}
+
+func (s StubWallet) CreateGoal(ctx context.Context, walletID string, g Goal) (Goal, error) {
+	return s.goal, s.err
+}
+
+func (s StubWallet) DeleteGoal(ctx context.Context, walletID, goalID string) (Goal, error) {
+	return s.goal, s.err
+}
+
+func (s StubWallet) Categories(ctx context.Context) ([]Category, error) {
+	return s.categories, s.err
+}
+
+func (s StubWallet) CreateCategory(ctx context.Context, c Category) (Category, error) {
+	return s.category, s.err
+}
+
+func (s StubWallet) UpdateCategory(ctx context.Context, name string, c Category) (Category, error) {
+	return s.category, s.err
+}
+
+func (s StubWallet) DeleteCategory(ctx context.Context, name string) (Category, error) {
+	return s.category, s.err
+}
+
+func (s StubWallet) CategoryRules(ctx context.Context) ([]CategoryRule, error) {
+	return s.rules, s.err
+}
+
+func (s StubWallet) CreateCategoryRule(ctx context.Context, r CategoryRule) (CategoryRule, error) {
+	return s.rule, s.err
+}
+
+func (s StubWallet) DeleteCategoryRule(ctx context.Context, id string) (CategoryRule, error) {
+	return s.rule, s.err
+}
+
+func (s StubWallet) WalletSpending(ctx context.Context, walletID string, from, to time.Time) (SpendingReport, error) {
+	return s.spending, s.err
+}
+
+func (s StubWallet) UserSpending(ctx context.Context, userID string, from, to time.Time) (SpendingReport, error) {
+	return s.spending, s.err
+}
+
+func (s StubWallet) CheckLedger(ctx context.Context, now time.Time) (LedgerCheck, error) {
+	return s.check, s.err
+}
+
+func (s StubWallet) LedgerAccounts(ctx context.Context) ([]Account, error) {
+	return s.accounts, s.err
+}
+
+func (s StubWallet) JournalEntry(ctx context.Context, id string) (JournalEntry, error) {
+	return s.entry, s.err
+}
+
+func (s StubWallet) Reconcile(ctx context.Context, fix bool) (ReconciliationReport, error) {
+	report := s.report
+	report.Fix = fix
+	return report, s.err
+}
+
+func (s StubWallet) ReconciliationReports(ctx context.Context) ([]ReconciliationReport, error) {
+	return s.reports, s.err
+}
+
+func (s StubWallet) ReconciliationReport(ctx context.Context, id string) (ReconciliationReport, error) {
+	return s.report, s.err
+}
+
+func (s StubWallet) WalletTypes(ctx context.Context) ([]Type, error) {
+	return s.walletTypes, s.err
+}
+
+func (s StubWallet) CreateWalletType(ctx context.Context, t Type) (Type, error) {
+	return s.walletType, s.err
+}
+
+func (s StubWallet) UpdateWalletType(ctx context.Context, name string, t Type) (Type, error) {
+	return s.walletType, s.err
+}
+
+func (s StubWallet) RetireWalletType(ctx context.Context, name string) (Type, error) {
+	return s.walletType, s.err
+}
+
 func TestWallet(t *testing.T) {
 	t.Run("given unable to get wallets should return 500 and error message", func(t *testing.T) {
 		e := echo.New()
@@ -135,4 +410,684 @@ func TestWallet(t *testing.T) {
 			t.Errorf("expected %v but got %v", want, got)
 		}
 	})
+
+	t.Run("given unknown wallet id when updating should return 404 with request id", func(t *testing.T) {
+		e := echo.New()
+		body := strings.NewReader(`{"user_id":1,"user_name":"John Doe","wallet_name":"John Savings","wallet_type":"Savings","balance":10}`)
+		req := httptest.NewRequest(http.MethodPut, "/", body)
+		req = req.WithContext(logging.WithRequestID(req.Context(), "req-42"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/wallets/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("99")
+
+		p := New(StubWallet{err: ErrNotFound})
+
+		p.UpdateWalletHandler(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status code %d but got %d", http.StatusNotFound, rec.Code)
+		}
+		var got Err
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		want := Err{Message: ErrNotFound.Error(), RequestID: "req-42"}
+		if got != want {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given deposit should return new balance and transaction", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":50}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/wallets/:id/deposits")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		tx := Transaction{ID: 7, WalletID: 1, Kind: KindDeposit, Amount: 50, BalanceAfter: 1050}
+		p := New(StubWallet{transaction: tx})
+
+		p.DepositHandler(c)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected status code %d but got %d", http.StatusCreated, rec.Code)
+		}
+		var got MovementResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		want := MovementResult{Balance: 1050, Transaction: tx}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given non-positive amount should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":-5}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.WithdrawalHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given overdraft should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":5000}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrInsufficientFunds})
+
+		p.WithdrawalHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given duplicate wallet type name should return 409", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Savings","decimals":2}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{err: ErrTypeExists})
+
+		p.CreateWalletTypeHandler(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status code %d but got %d", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("given retired wallet type when creating wallet should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"user_id":1,"wallet_type":"Gift Card"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{err: ErrTypeRetired})
+
+		p.CreateWalletHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given unknown statement should return 404", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/wallets/:id/statements/:statement_id")
+		c.SetParamNames("id", "statement_id")
+		c.SetParamValues("1", "99")
+
+		p := New(StubWallet{err: ErrStatementNotFound})
+
+		p.StatementHandler(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status code %d but got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("given wallet type without interest when previewing should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/wallets/:id/interest/preview")
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+
+		p := New(StubWallet{err: ErrNoInterest})
+
+		p.InterestPreviewHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given quantity with more than 18 decimals should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"asset":"eth","quantity":"0.0000000000000000001"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("3")
+
+		p := New(StubWallet{})
+
+		p.AddHoldingHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given expiry in the past when placing hold should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":40,"expires_at":"2020-01-01T00:00:00Z"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.PlaceHoldHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given inactive hold when capturing should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":10}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "hold_id")
+		c.SetParamValues("1", "5")
+
+		p := New(StubWallet{err: ErrHoldNotActive})
+
+		p.CaptureHoldHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given transfer to the same wallet should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from_wallet_id":1,"to_wallet_id":1,"amount":10}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{})
+
+		p.CreateTransferHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given cancelled scheduled transfer when resuming should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrScheduleState})
+
+		p.ResumeScheduledTransferHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given budget on both a wallet and a user should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"wallet_id":1,"user_id":1,"amount":500,"period":"monthly"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{})
+
+		p.CreateBudgetHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given wallet budgets should return remaining amounts", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		walletID := 1
+		start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		want := []BudgetStatus{NewBudgetStatus(Budget{ID: 1, WalletID: &walletID, Amount: 500, Period: PeriodMonthly}, start, 420)}
+		p := New(StubWallet{budgets: want})
+
+		p.WalletBudgetsHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+		var got []BudgetStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given goal on a wallet type without goals should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Holiday","target_amount":3000,"target_date":"2024-12-01T00:00:00Z"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrNoGoals})
+
+		p.CreateGoalHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given goal rounding up into its own wallet should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Holiday","target_amount":3000,"target_date":"2024-12-01T00:00:00Z","round_up_wallet_id":1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrInvalidRoundUp})
+
+		p.CreateGoalHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given withdrawal with too many tags should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":10,"tags":["a","b","c","d","e","f","g","h","i","j","k"]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.WithdrawalHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given category in use when deleting should return 409", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("name")
+		c.SetParamValues("groceries")
+
+		p := New(StubWallet{err: ErrCategoryInUse})
+
+		p.DeleteCategoryHandler(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status code %d but got %d", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("given invalid report range should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?from=2024-04-01&to=2024-03-01", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.WalletSpendingHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given user wallets should return summary", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		change := 7.7
+		want := UserSummary{
+			UserID:      1,
+			WalletCount: 2,
+			Currencies:  []CurrencySummary{{Currency: "USD", WalletCount: 2, Assets: 5000, Debt: 800, NetWorth: 4200, MonthStartNetWorth: 3900, MonthChange: 300, MonthChangePercent: &change}},
+			WalletTypes: []TypeSummary{{WalletType: "Credit Card", Currency: "USD", Count: 1, Balance: -800}, {WalletType: "Savings", Currency: "USD", Count: 1, Balance: 5000}},
+			MonthStart:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}
+		p := New(StubWallet{summary: want})
+
+		p.UserSummaryHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+		var got UserSummary
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given transfer between currencies should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from_wallet_id":1,"to_wallet_id":2,"amount":10}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{err: ErrCurrencyMismatch})
+
+		p.CreateTransferHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given as_of date should return balance at end of day", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=2024-03-31", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		want := Balance{WalletID: 1, AsOf: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Balance: 1250, Currency: "USD"}
+		p := New(StubWallet{balance: want})
+
+		p.BalanceHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+		var got Balance
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given invalid as_of should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=last-month", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.BalanceHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given ledger check should return report", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		want := LedgerCheck{
+			CheckedAt:         time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			Totals:            []CurrencyTotal{{Currency: "USD", Total: 0}},
+			UnbalancedEntries: []UnbalancedEntry{},
+			MismatchCount:     1,
+			BalanceMismatches: []BalanceMismatch{{WalletID: 1, Cached: 100, Derived: 90}},
+			Clearing:          []CurrencyTotal{},
+		}
+		p := New(StubWallet{check: want})
+
+		p.LedgerCheckHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+		var got LedgerCheck
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given unknown journal entry should return 404", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("999")
+
+		p := New(StubWallet{err: ErrEntryNotFound})
+
+		p.JournalEntryHandler(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status code %d but got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("given fix should reconcile and return 201", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/?fix=true", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		adjustmentID := int64(431)
+		p := New(StubWallet{report: ReconciliationReport{
+			ID:               12,
+			WalletsChecked:   3,
+			DiscrepancyCount: 1,
+			Discrepancies:    []Discrepancy{{WalletID: 1, Cached: 100, Ledger: 90, AdjustmentID: &adjustmentID}},
+			CreatedAt:        time.Date(2024, 4, 1, 2, 0, 0, 0, time.UTC),
+		}})
+
+		p.ReconcileHandler(c)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected status code %d but got %d", http.StatusCreated, rec.Code)
+		}
+		var got ReconciliationReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !got.Fix || len(got.Discrepancies) != 1 || *got.Discrepancies[0].AdjustmentID != adjustmentID {
+			t.Errorf("expected a fixed report with adjustment %d but got %+v", adjustmentID, got)
+		}
+	})
+
+	t.Run("given invalid fix should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/?fix=maybe", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		p := New(StubWallet{})
+
+		p.ReconcileHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given unknown reconciliation report should return 404", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("999")
+
+		p := New(StubWallet{err: ErrReportNotFound})
+
+		p.ReconciliationReportHandler(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status code %d but got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("given partial refund should return 201 with refund state", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":100,"reason":"Duplicate payment"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		want := Transfer{
+			ID: 1, FromWalletID: 1, ToWalletID: 2, Amount: 500, DebitTransactionID: 10, CreditTransactionID: 11,
+			Status: TransferPartiallyRefunded, RefundedAmount: 100,
+			Refunds: []TransferRefund{{ID: 1, TransferID: 1, Amount: 100, Reason: "Duplicate payment", DebitTransactionID: 12, CreditTransactionID: 13,
+				CreatedAt: time.Date(2024, 3, 26, 9, 0, 0, 0, time.UTC)}},
+			CreatedAt: time.Date(2024, 3, 25, 14, 19, 0, 0, time.UTC),
+		}
+		p := New(StubWallet{transfer: want})
+
+		p.RefundTransferHandler(c)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected status code %d but got %d", http.StatusCreated, rec.Code)
+		}
+		var got Transfer
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("unable to unmarshal json: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("given refund without amount should return 400", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reason":"Duplicate payment"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{})
+
+		p.RefundTransferHandler(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("given refund beyond transfer amount should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":600}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrRefundExceedsAmount})
+
+		p.RefundTransferHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("given reversal without body should return 200", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		reversedAt := time.Date(2024, 3, 26, 9, 0, 0, 0, time.UTC)
+		p := New(StubWallet{transfer: Transfer{ID: 1, Amount: 500, RefundedAmount: 500, Status: TransferReversed, ReversedAt: &reversedAt}})
+
+		p.ReverseTransferHandler(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("given reversal of refunded transfer should return 422", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		p := New(StubWallet{err: ErrTransferRefunded})
+
+		p.ReverseTransferHandler(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status code %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
 }
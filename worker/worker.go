@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Func is a single run of a background job. The context is cancelled when
+// the runner is stopped, and jobs should return promptly once it is.
+type Func func(ctx context.Context) error
+
+// Runner owns the application's background jobs so they can be stopped
+// together during shutdown.
+type Runner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New() *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{ctx: ctx, cancel: cancel}
+}
+
+// Every runs fn once per interval until the runner is stopped. A run that
+// is in progress when Stop is called is allowed to finish.
+func (r *Runner) Every(name string, interval time.Duration, fn Func) {
+	r.start(name, interval, fn, false)
+}
+
+// EveryNow is Every with a first run as soon as it is started, for jobs
+// whose interval is long enough that waiting for the first tick, on every
+// restart, would leave them effectively never running.
+func (r *Runner) EveryNow(name string, interval time.Duration, fn Func) {
+	r.start(name, interval, fn, true)
+}
+
+func (r *Runner) start(name string, interval time.Duration, fn Func, now bool) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		run := func() {
+			if err := fn(r.ctx); err != nil {
+				slog.Error("background job failed", "worker", name, "error", err)
+			}
+		}
+		if now {
+			run()
+		}
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-t.C:
+				run()
+			}
+		}
+	}()
+}
+
+// Stop cancels all jobs and waits for them to return or for ctx to expire.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.cancel()
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
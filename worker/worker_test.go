@@ -0,0 +1,73 @@
+//go:build unit
+
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	t.Run("given running job should run periodically until stopped", func(t *testing.T) {
+		r := New()
+		var runs atomic.Int32
+		r.Every("count", time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+
+		time.Sleep(20 * time.Millisecond)
+		if err := r.Stop(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stopped := runs.Load()
+		time.Sleep(10 * time.Millisecond)
+
+		if stopped == 0 {
+			t.Error("expected job to have run")
+		}
+		if runs.Load() != stopped {
+			t.Error("expected job not to run after stop")
+		}
+	})
+
+	t.Run("given job that ignores cancellation should give up at deadline", func(t *testing.T) {
+		r := New()
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+		defer close(release)
+		r.Every("stuck", time.Millisecond, func(ctx context.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		})
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := r.Stop(ctx); err == nil {
+			t.Error("expected deadline error")
+		}
+	})
+
+	t.Run("given job started now should run before the first tick", func(t *testing.T) {
+		r := New()
+		ran := make(chan struct{}, 1)
+		r.EveryNow("now", time.Hour, func(ctx context.Context) error {
+			ran <- struct{}{}
+			return nil
+		})
+		defer r.Stop(context.Background())
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Error("expected job to run at once")
+		}
+	})
+}